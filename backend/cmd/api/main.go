@@ -1,90 +1,322 @@
 package main
 
 import (
-	"log"
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/modmastei2/Go-next/backend/config"
+	"github.com/modmastei2/Go-next/backend/internal/domain"
 	"github.com/modmastei2/Go-next/backend/internal/handler"
 	"github.com/modmastei2/Go-next/backend/internal/middleware"
 	"github.com/modmastei2/Go-next/backend/internal/repository"
 	"github.com/modmastei2/Go-next/backend/internal/usecase"
 	"github.com/modmastei2/Go-next/backend/pkg/database"
+	"github.com/modmastei2/Go-next/backend/pkg/health"
+	"github.com/modmastei2/Go-next/backend/pkg/lifecycle"
+	"github.com/modmastei2/Go-next/backend/pkg/logging"
+	"github.com/modmastei2/Go-next/backend/pkg/metrics"
+	"github.com/modmastei2/Go-next/backend/pkg/notify"
+	"github.com/modmastei2/Go-next/backend/pkg/ratelimit"
+	"github.com/modmastei2/Go-next/backend/pkg/token"
+	"github.com/modmastei2/Go-next/backend/pkg/tracing"
 )
 
+// version is set at build time with -ldflags "-X main.version=1.2.3"
+var version = "dev"
+
 func main() {
 	// Load configuration
 	cfg := config.Load()
 
+	// Initialize structured logging, JSON in production and text in development
+	logger, err := logging.New(cfg.Logging, os.Stdout)
+	if err != nil {
+		fatal("Invalid logging configuration", err)
+	}
+	slog.SetDefault(logger)
+
 	// Initialize database
 	db, err := database.NewDatabase(&cfg.Database)
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		fatal("Failed to connect to database", err)
 	}
 
-	// Run migrations
-	if err := database.MigrateDatabase(db); err != nil {
-		log.Fatal("Failed to migrate database:", err)
+	// Handle `migrate up|down|status` instead of starting the server
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(db, os.Args[2:]); err != nil {
+			fatal("Migration failed", err)
+		}
+		return
+	}
+
+	// Run migrations, or refuse to start on an outdated schema
+	if cfg.Database.AutoMigrate {
+		if err := database.MigrateDatabase(db); err != nil {
+			fatal("Failed to migrate database", err)
+		}
+	} else if err := database.CheckMigrations(db); err != nil {
+		fatal("Database schema is not up to date", err)
 	}
 
 	// Seed database
 	if err := database.SeedDatabase(db); err != nil {
-		log.Fatal("Failed to seed database:", err)
+		fatal("Failed to seed database", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		fatal("Failed to access database pool", err)
+	}
+
+	// Initialize metrics of the connection pool, queries and business events
+	registry := metrics.NewRegistry()
+	metrics.RegisterDBStats(registry, sqlDB, cfg.Database.Database)
+	if err := db.Use(metrics.NewGormPlugin(registry)); err != nil {
+		fatal("Failed to install query metrics", err)
+	}
+	httpMetrics := metrics.NewHTTP(registry)
+	orderMetrics := metrics.NewOrders(registry)
+
+	// Initialize tracing of requests, usecases and queries
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, version)
+	if err != nil {
+		fatal("Invalid tracing configuration", err)
+	}
+	if err := db.Use(tracing.NewGormPlugin()); err != nil {
+		fatal("Failed to install query tracing", err)
+	}
+
+	if err := cfg.CORS.Validate(); err != nil {
+		fatal("Invalid CORS configuration", err)
+	}
+
+	// Initialize access token verification
+	verifier, err := token.NewVerifier(cfg.Auth)
+	if err != nil {
+		fatal("Invalid JWT configuration", err)
+	}
+
+	// Initialize access token issuing, without a signing key the server only
+	// verifies tokens and customers cannot sign in here
+	var signer *token.Signer
+	if cfg.Auth.CanSign() {
+		signer, err = token.NewSigner(cfg.Auth)
+		if err != nil {
+			fatal("Invalid JWT signing configuration", err)
+		}
+	} else {
+		slog.Warn("No JWT private key configured, login and token refresh are disabled")
+	}
+
+	// Initialize notifications such as password reset tokens
+	notifier, err := notify.New(cfg.Notifier)
+	if err != nil {
+		fatal("Invalid notifier configuration", err)
 	}
 
 	// Dependency Injection - Initialize repositories
+	customerRepo := repository.NewCustomerRepository(db)
 	orderRepo := repository.NewOrderRepository(db)
 	productRepo := repository.NewProductRepository(db)
+	historyRepo := repository.NewOrderStatusHistoryRepository(db)
+	credentialRepo := repository.NewCredentialRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	resetTokenRepo := repository.NewPasswordResetRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	txManager := repository.NewTxManager(db)
 
 	// Dependency Injection - Initialize usecases
-	orderUsecase := usecase.NewOrderUsecase(orderRepo, productRepo)
-	productUsecase := usecase.NewProductUsecase(productRepo)
+	orderUsecase := usecase.NewTracedOrderUsecase(
+		usecase.NewOrderUsecase(orderRepo, productRepo, historyRepo, txManager, orderMetrics, cfg.Pagination.MaxLimit))
+	productUsecase := usecase.NewTracedProductUsecase(usecase.NewProductUsecase(productRepo, cfg.Pagination.MaxLimit))
+	customerUsecase := usecase.NewTracedCustomerUsecase(usecase.NewCustomerUsecase(customerRepo, orderRepo, cfg.Pagination.MaxLimit))
+	authUsecase := usecase.NewTracedAuthUsecase(usecase.NewAuthUsecase(customerRepo, credentialRepo, refreshTokenRepo, resetTokenRepo, txManager,
+		signer, notifier, cfg.Accounts.RefreshTokenTTL, cfg.Accounts.PasswordResetTTL))
+	apiKeyUsecase := usecase.NewTracedAPIKeyUsecase(usecase.NewAPIKeyUsecase(apiKeyRepo))
 
 	// Dependency Injection - Initialize handlers
 	orderHandler := handler.NewOrderHandler(orderUsecase)
 	productHandler := handler.NewProductHandler(productUsecase)
+	customerHandler := handler.NewCustomerHandler(customerUsecase)
+	authHandler := handler.NewAuthHandler(authUsecase)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyUsecase)
+
+	// Server lifecycle, flushes traces and closes the database on shutdown
+	lc := lifecycle.New()
+	lc.OnStop("database", sqlDB.Close)
+	lc.OnStop("tracing", func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), tracingFlushTimeout)
+		defer cancel()
+		return shutdownTracing(ctx)
+	})
+
+	// Readiness checks, not ready while draining so no new traffic is routed here
+	checks := health.NewRegistry(health.NewBuild(version), cfg.Server.HealthCheckTimeout)
+	checks.Register("server", health.CheckerFunc(func(ctx context.Context) error {
+		if !lc.Ready() {
+			return health.Unavailable("draining", nil)
+		}
+		return nil
+	}))
+	checks.Register("database", health.CheckerFunc(sqlDB.PingContext))
+	checks.Register("migrations", health.CheckerFunc(func(ctx context.Context) error {
+		if err := database.CheckMigrations(db.WithContext(ctx)); err != nil {
+			return health.Unavailable("pending migrations", err)
+		}
+		return nil
+	}))
+	healthHandler := handler.NewHealthHandler(checks)
 
 	// Initialize Fiber app
 	app := fiber.New(fiber.Config{
-		AppName: "Shop Order API",
+		AppName:      "Shop Order API",
+		ErrorHandler: handler.ErrorHandler,
+		// The banner would break JSON log parsing
+		DisableStartupMessage: cfg.Logging.Format == logging.FormatJSON,
+		// Behind a load balancer the client IP is read from its header, but
+		// only on requests that come from one of the trusted proxies
+		ProxyHeader:             cfg.Server.ProxyHeader,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          cfg.Server.TrustedProxies,
+		EnableIPValidation:      true,
 	})
 
+	// Rate limit buckets of every route group and of rejected credentials
+	rateLimits := ratelimit.NewMemoryStore()
+
 	// Apply global middleware
-	app.Use(middleware.Recover())
+	app.Use(middleware.BaseContext(lc.Context()))
+	app.Use(middleware.Tracing())
+	app.Use(middleware.Metrics(httpMetrics))
 	app.Use(middleware.Logger())
-	app.Use(middleware.CORS())
+	// Inside Tracing, Metrics and Logger so a panic is recorded as a 500
+	app.Use(middleware.Recover())
+	app.Use(middleware.CORS(cfg.CORS))
 	app.Use(middleware.RequestID())
+	app.Use(middleware.Timeout(cfg.Server.RequestTimeout))
+	app.Use(middleware.LimitAuthFailures(rateLimits, cfg.RateLimit.AuthFailures))
+	app.Use(middleware.Authenticate(verifier, apiKeyUsecase))
 
-	// Health check endpoint
-	app.Get("/health", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"status": "healthy",
-		})
-	})
+	// Route role requirements, API keys are allowed by scope
+	admin := middleware.RequireRole(domain.RoleAdmin)
+	users := []domain.Role{domain.RoleAdmin, domain.RoleStaff, domain.RoleCustomer}
+	staff := []domain.Role{domain.RoleAdmin, domain.RoleStaff}
 
-	// API routes
-	api := app.Group("/api")
+	// Health probes, /health is kept as an alias of readiness
+	app.Get("/health/live", healthHandler.Live)
+	app.Get("/health/ready", healthHandler.Ready)
+	app.Get("/health", healthHandler.Ready)
 
-	// Product routes
+	// Prometheus metrics, scraped with an API key or by admins
+	if cfg.Metrics.Enabled {
+		app.Get("/metrics", middleware.Allow(domain.ScopeMetricsRead, domain.RoleAdmin),
+			adaptor.HTTPHandler(metrics.Handler(registry)))
+	}
+
+	// API routes, rate limited per API key, user or IP
+	api := app.Group("/api", middleware.RateLimit(rateLimits, "api", cfg.RateLimit.API))
+
+	// Account routes, public so customers can sign up and sign in
+	auth := api.Group("/auth", middleware.RateLimit(rateLimits, "auth", cfg.RateLimit.Auth))
+	auth.Post("/register", authHandler.Register)
+	if signer != nil {
+		auth.Post("/login", authHandler.Login)
+		auth.Post("/refresh", authHandler.Refresh)
+	}
+	auth.Post("/logout", authHandler.Logout)
+	auth.Post("/password-reset", authHandler.RequestPasswordReset)
+	auth.Post("/password-reset/confirm", authHandler.ResetPassword)
+
+	// Product routes, the catalog is public
 	products := api.Group("/products")
 	products.Get("/", productHandler.GetProducts)
 	products.Get("/:id", productHandler.GetProduct)
-	products.Post("/", productHandler.CreateProduct)
-	products.Put("/:id", productHandler.UpdateProduct)
-	products.Delete("/:id", productHandler.DeleteProduct)
+	products.Post("/", middleware.Allow(domain.ScopeProductsWrite, staff...), productHandler.CreateProduct)
+	products.Put("/:id", middleware.Allow(domain.ScopeProductsWrite, staff...), productHandler.UpdateProduct)
+	products.Patch("/:id", middleware.Allow(domain.ScopeProductsWrite, staff...), productHandler.PatchProduct)
+	products.Delete("/:id", admin, productHandler.DeleteProduct)
+
+	// Customer routes, customers may only read their own record and orders
+	customers := api.Group("/customers")
+	customers.Get("/", middleware.Allow(domain.ScopeCustomersRead, staff...), customerHandler.GetCustomers)
+	customers.Get("/lookup", middleware.Allow(domain.ScopeCustomersRead, staff...), customerHandler.GetCustomerByEmail)
+	customers.Get("/:id", middleware.Allow(domain.ScopeCustomersRead, users...), customerHandler.GetCustomer)
+	customers.Get("/:id/orders", middleware.Allow(domain.ScopeOrdersRead, users...), customerHandler.GetCustomerOrders)
+	customers.Post("/", middleware.Allow(domain.ScopeCustomersWrite, staff...), customerHandler.CreateCustomer)
+	customers.Put("/:id", middleware.Allow(domain.ScopeCustomersWrite, staff...), customerHandler.UpdateCustomer)
+	customers.Delete("/:id", admin, customerHandler.DeleteCustomer)
 
-	// Order routes
+	// Order routes, customers may only read and create their own orders
 	orders := api.Group("/orders")
-	orders.Get("/", orderHandler.GetOrders)
-	orders.Get("/:id", orderHandler.GetOrder)
-	orders.Post("/", orderHandler.CreateOrder)
-	orders.Put("/:id/status", orderHandler.UpdateOrderStatus)
-	orders.Delete("/:id", orderHandler.DeleteOrder)
+	orders.Get("/", middleware.Allow(domain.ScopeOrdersRead, users...), orderHandler.GetOrders)
+	orders.Get("/:id", middleware.Allow(domain.ScopeOrdersRead, users...), orderHandler.GetOrder)
+	orders.Post("/", middleware.RateLimit(rateLimits, "orders:create", cfg.RateLimit.OrderCreate),
+		middleware.Allow(domain.ScopeOrdersWrite, users...), orderHandler.CreateOrder)
+	orders.Put("/:id/status", middleware.Allow(domain.ScopeOrdersWrite, staff...), orderHandler.UpdateOrderStatus)
+	orders.Get("/:id/history", middleware.Allow(domain.ScopeOrdersRead, users...), orderHandler.GetOrderHistory)
+	orders.Delete("/:id", admin, orderHandler.DeleteOrder)
+
+	// API key routes, only admins manage integrations
+	apiKeys := api.Group("/api-keys", admin)
+	apiKeys.Get("/", apiKeyHandler.GetAPIKeys)
+	apiKeys.Post("/", apiKeyHandler.CreateAPIKey)
+	apiKeys.Delete("/:id", apiKeyHandler.RevokeAPIKey)
 
 	// Start server
 	serverAddr := cfg.Server.Host + ":" + cfg.Server.Port
-	log.Printf("Server starting on %s", serverAddr)
-	if err := app.Listen(serverAddr); err != nil {
-		log.Fatal("Failed to start server:", err)
+	slog.Info("Server starting", "addr", serverAddr)
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- app.Listen(serverAddr)
+	}()
+
+	// Run until SIGINT or SIGTERM, or until the listener fails
+	signals, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	select {
+	case err := <-listenErr:
+		if err != nil {
+			fatal("Failed to start server", err)
+		}
+	case <-signals.Done():
+	}
+	stop()
+
+	shutdown(app, lc, cfg.Server.ShutdownDelay, cfg.Server.ShutdownTimeout)
+}
+
+// tracingFlushTimeout is how long buffered spans get to export on shutdown
+const tracingFlushTimeout = 5 * time.Second
+
+// shutdown stops the server gracefully. Readiness turns false first and, after
+// delay, the listener closes; in-flight requests then get until timeout to
+// finish before their contexts are canceled and the database closes.
+func shutdown(app *fiber.App, lc *lifecycle.Lifecycle, delay, timeout time.Duration) {
+	slog.Info("Shutting down", "delay", delay, "timeout", timeout)
+	lc.Drain()
+	time.Sleep(delay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := app.ShutdownWithContext(ctx); err != nil {
+		slog.Warn("In-flight requests did not finish in time, canceling them", "error", err)
+	}
+
+	if err := lc.Stop(); err != nil {
+		slog.Error("Shutdown incomplete", "error", err)
+		return
 	}
+	slog.Info("Server stopped")
+}
+
+// fatal logs an error that prevents the server from running and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/modmastei2/Go-next/backend/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const migrateUsage = `Usage: api migrate <command> [flags]
+
+Commands:
+  up [--dry-run]     apply all pending migrations, or print their SQL
+  down [--steps N]   revert the last N applied migrations (default 1)
+  status             list migrations and whether they are applied`
+
+// runMigrate executes the `migrate` subcommand
+func runMigrate(db *gorm.DB, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return errors.New("missing migrate command")
+	}
+
+	// Keep SQL tracing out of the command output, dry-run prints it explicitly
+	db = db.Session(&gorm.Session{Logger: db.Logger.LogMode(logger.Error)})
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	flags := flag.NewFlagSet("migrate "+args[0], flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "print the SQL of pending migrations without applying them")
+	steps := flags.Int("steps", 1, "number of migrations to revert")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		if *dryRun {
+			plans, err := migrator.DryRun()
+			if err != nil {
+				return err
+			}
+			if len(plans) == 0 {
+				fmt.Println("No pending migrations")
+			}
+			for _, plan := range plans {
+				fmt.Printf("-- %04d %s\n", plan.Migration.Version, plan.Migration.Name)
+				for _, statement := range plan.Statements {
+					fmt.Printf("%s;\n", statement)
+				}
+				fmt.Println()
+			}
+			return nil
+		}
+
+		applied, err := migrator.Up()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Applied %d migration(s)\n", applied)
+
+	case "down":
+		if *steps < 1 {
+			return errors.New("--steps must be at least 1")
+		}
+		reverted, err := migrator.Down(*steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Reverted %d migration(s)\n", reverted)
+
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		return w.Flush()
+
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+	return nil
+}
@@ -2,20 +2,72 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/modmastei2/Go-next/backend/pkg/cors"
 	"github.com/modmastei2/Go-next/backend/pkg/database"
+	"github.com/modmastei2/Go-next/backend/pkg/logging"
+	"github.com/modmastei2/Go-next/backend/pkg/notify"
+	"github.com/modmastei2/Go-next/backend/pkg/ratelimit"
+	"github.com/modmastei2/Go-next/backend/pkg/token"
+	"github.com/modmastei2/Go-next/backend/pkg/tracing"
 )
 
 // Config holds all application configuration
 type Config struct {
-	Server   ServerConfig
-	Database database.Config
+	Server     ServerConfig
+	Database   database.Config
+	Pagination PaginationConfig
+	Auth       token.Config
+	Accounts   AccountConfig
+	Notifier   notify.Config
+	RateLimit  RateLimitConfig
+	CORS       cors.Config
+	Logging    logging.Config
+	Metrics    MetricsConfig
+	Tracing    tracing.Config
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
-	Port string
-	Host string
+	Port           string
+	Host           string
+	RequestTimeout time.Duration // deadline of the request context, zero disables it
+
+	ProxyHeader    string   // header a reverse proxy sets to the client IP, such as X-Forwarded-For
+	TrustedProxies []string // IPs or CIDR ranges of the proxies whose ProxyHeader is believed
+
+	ShutdownDelay   time.Duration // time between reporting not ready and closing the listener
+	ShutdownTimeout time.Duration // time in-flight requests get to finish on shutdown
+
+	HealthCheckTimeout time.Duration // time each readiness check may take
+}
+
+// PaginationConfig holds listing page size limits
+type PaginationConfig struct {
+	MaxLimit int // largest page size a client may request
+}
+
+// AccountConfig holds customer account token lifetimes
+type AccountConfig struct {
+	RefreshTokenTTL  time.Duration // lifetime of refresh tokens
+	PasswordResetTTL time.Duration // lifetime of password reset tokens
+}
+
+// RateLimitConfig holds the request budgets of route groups, zero limits are disabled
+type RateLimitConfig struct {
+	API         ratelimit.Limit // every /api route
+	Auth        ratelimit.Limit // login, registration and password reset
+	OrderCreate ratelimit.Limit // placing orders
+
+	AuthFailures ratelimit.Limit // rejected credentials per client IP
+}
+
+// MetricsConfig holds the Prometheus endpoint configuration
+type MetricsConfig struct {
+	Enabled bool // serve /metrics
 }
 
 // Load loads configuration from environment variables
@@ -24,15 +76,85 @@ func Load() *Config {
 		Server: ServerConfig{
 			Port: getEnv("SERVER_PORT", "3001"),
 			Host: getEnv("SERVER_HOST", "0.0.0.0"),
+
+			RequestTimeout: getEnvDuration("SERVER_REQUEST_TIMEOUT", 30*time.Second),
+
+			ProxyHeader:    os.Getenv("SERVER_PROXY_HEADER"),
+			TrustedProxies: getEnvList("SERVER_TRUSTED_PROXIES", ""),
+
+			ShutdownDelay:   getEnvDuration("SERVER_SHUTDOWN_DELAY", 5*time.Second),
+			ShutdownTimeout: getEnvDuration("SERVER_SHUTDOWN_TIMEOUT", 20*time.Second),
+
+			HealthCheckTimeout: getEnvDuration("SERVER_HEALTH_CHECK_TIMEOUT", 2*time.Second),
 		},
 		Database: database.Config{
 			Driver:   getEnv("DB_DRIVER", "sqlserver"),
 			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnv("DB_PORT", "1433"),
+			Port:     getEnv("DB_PORT", ""),
 			User:     getEnv("DB_USER", "sa"),
 			Password: getEnv("DB_PASSWORD", "S1u8p3a8#"),
 			Database: getEnv("DB_NAME", "ResearchDB"),
+			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+
+			AutoMigrate: getEnvBool("DB_AUTO_MIGRATE", true),
+		},
+		Pagination: PaginationConfig{
+			MaxLimit: getEnvInt("PAGINATION_MAX_LIMIT", 100),
+		},
+		Auth: token.Config{
+			Algorithm:      getEnv("JWT_ALGORITHM", token.AlgorithmHS256),
+			Secret:         os.Getenv("JWT_SECRET"),
+			PublicKeyFile:  os.Getenv("JWT_PUBLIC_KEY_FILE"),
+			PrivateKeyFile: os.Getenv("JWT_PRIVATE_KEY_FILE"),
+			Issuer:         os.Getenv("JWT_ISSUER"),
+			Audience:       os.Getenv("JWT_AUDIENCE"),
+			AccessTTL:      getEnvDuration("JWT_ACCESS_TTL", 15*time.Minute),
+		},
+		Accounts: AccountConfig{
+			RefreshTokenTTL:  getEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+			PasswordResetTTL: getEnvDuration("PASSWORD_RESET_TTL", time.Hour),
+		},
+		Notifier: notify.Config{
+			Driver: getEnv("NOTIFIER", notify.DriverLog),
+			File:   getEnv("NOTIFIER_FILE", "notifications.log"),
+		},
+		RateLimit: loadRateLimit(),
+		Logging: logging.Config{
+			Format: getEnv("LOG_FORMAT", logging.FormatJSON),
+			Level:  getEnv("LOG_LEVEL", "info"),
 		},
+		CORS: cors.Config{
+			AllowOrigins:     getEnvList("CORS_ALLOW_ORIGINS", "http://localhost:3000"),
+			AllowMethods:     getEnvList("CORS_ALLOW_METHODS", "GET,POST,PUT,PATCH,DELETE,OPTIONS"),
+			AllowHeaders:     getEnvList("CORS_ALLOW_HEADERS", "Origin,Content-Type,Accept,Authorization,X-Request-ID,traceparent,tracestate"),
+			ExposeHeaders:    getEnvList("CORS_EXPOSE_HEADERS", "X-Request-ID,traceparent,Link,Retry-After,RateLimit-Policy,RateLimit-Limit,RateLimit-Remaining,RateLimit-Reset"),
+			AllowCredentials: getEnvBool("CORS_ALLOW_CREDENTIALS", false),
+			MaxAge:           getEnvDuration("CORS_MAX_AGE", 10*time.Minute),
+		},
+		Metrics: MetricsConfig{
+			Enabled: getEnvBool("METRICS_ENABLED", true),
+		},
+		Tracing: tracing.Config{
+			Exporter:    getEnv("TRACING_EXPORTER", tracing.ExporterNone),
+			Endpoint:    getEnv("TRACING_OTLP_ENDPOINT", ""),
+			Insecure:    getEnvBool("TRACING_OTLP_INSECURE", false),
+			SampleRatio: getEnvFloat("TRACING_SAMPLE_RATIO", 1),
+			ServiceName: getEnv("TRACING_SERVICE_NAME", "shop-order-api"),
+		},
+	}
+}
+
+// loadRateLimit loads the rate limits unless RATE_LIMIT_ENABLED is false
+func loadRateLimit() RateLimitConfig {
+	if !getEnvBool("RATE_LIMIT_ENABLED", true) {
+		return RateLimitConfig{}
+	}
+	return RateLimitConfig{
+		API:         getEnvLimit("RATE_LIMIT_API", ratelimit.Limit{Requests: 300, Period: time.Minute}),
+		Auth:        getEnvLimit("RATE_LIMIT_AUTH", ratelimit.Limit{Requests: 10, Period: time.Minute}),
+		OrderCreate: getEnvLimit("RATE_LIMIT_ORDER_CREATE", ratelimit.Limit{Requests: 30, Period: time.Minute}),
+
+		AuthFailures: getEnvLimit("RATE_LIMIT_AUTH_FAILURES", ratelimit.Limit{Requests: 20, Period: time.Minute}),
 	}
 }
 
@@ -44,3 +166,59 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+// getEnvBool gets a boolean environment variable or returns a default value
+func getEnvBool(key string, defaultValue bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvInt gets an integer environment variable or returns a default value
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvFloat gets a floating point environment variable such as "0.25" or returns a default value
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvDuration gets a duration environment variable such as "15m" or returns a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvLimit gets a rate limit environment variable such as "100/1m" or returns a default value
+func getEnvLimit(key string, defaultValue ratelimit.Limit) ratelimit.Limit {
+	value, err := ratelimit.ParseLimit(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvList gets a comma separated environment variable or returns a default list
+func getEnvList(key, defaultValue string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, defaultValue), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,80 @@
+package domain
+
+import "time"
+
+// Credential holds the password hash of a customer account
+type Credential struct {
+	ID           uint   `gorm:"primaryKey"`
+	CustomerID   uint   `gorm:"uniqueIndex"`
+	PasswordHash string `gorm:"size:255"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// RefreshToken is an issued refresh token. Only a hash of the token is stored,
+// and each token is revoked as soon as it is exchanged for a new one.
+type RefreshToken struct {
+	ID         uint   `gorm:"primaryKey"`
+	CustomerID uint   `gorm:"index"`
+	TokenHash  string `gorm:"size:64;uniqueIndex"`
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// IsActive reports whether the token is neither revoked nor expired
+func (t *RefreshToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// PasswordResetToken is a one-time token for setting a new password, stored hashed
+type PasswordResetToken struct {
+	ID         uint   `gorm:"primaryKey"`
+	CustomerID uint   `gorm:"index"`
+	TokenHash  string `gorm:"size:64;uniqueIndex"`
+	ExpiresAt  time.Time
+	UsedAt     *time.Time
+	CreatedAt  time.Time
+}
+
+// IsActive reports whether the token is neither used nor expired
+func (t *PasswordResetToken) IsActive(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// RegisterRequest represents the request to sign up a customer account
+type RegisterRequest struct {
+	Name     string `json:"name" validate:"notblank,max=255"`
+	Email    string `json:"email" validate:"required,email,max=255"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+// LoginRequest represents the request to sign in with email and password
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// RefreshTokenRequest represents a request carrying a refresh token
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// PasswordResetRequest represents the request to send a password reset token
+type PasswordResetRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ConfirmPasswordResetRequest represents the request to set a new password with a reset token
+type ConfirmPasswordResetRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+// TokenResponse holds the tokens issued on login and refresh
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"` // access token lifetime in seconds
+	RefreshToken string `json:"refresh_token"`
+}
@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Scope is a permission granted to an API key
+type Scope string
+
+// Scopes of API keys
+const (
+	ScopeProductsRead   Scope = "products:read"
+	ScopeProductsWrite  Scope = "products:write"
+	ScopeCustomersRead  Scope = "customers:read"
+	ScopeCustomersWrite Scope = "customers:write"
+	ScopeOrdersRead     Scope = "orders:read"
+	ScopeOrdersWrite    Scope = "orders:write"
+	ScopeMetricsRead    Scope = "metrics:read"
+)
+
+// IsValid reports whether the scope is known
+func (s Scope) IsValid() bool {
+	switch s {
+	case ScopeProductsRead, ScopeProductsWrite, ScopeCustomersRead, ScopeCustomersWrite,
+		ScopeOrdersRead, ScopeOrdersWrite, ScopeMetricsRead:
+		return true
+	}
+	return false
+}
+
+// Scopes is a list of scopes, stored as one space separated column
+type Scopes []Scope
+
+// Has reports whether the list contains the scope
+func (s Scopes) Has(scope Scope) bool {
+	for _, granted := range s {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Value implements driver.Valuer
+func (s Scopes) Value() (driver.Value, error) {
+	values := make([]string, len(s))
+	for i, scope := range s {
+		values[i] = string(scope)
+	}
+	return strings.Join(values, " "), nil
+}
+
+// Scan implements sql.Scanner
+func (s *Scopes) Scan(src interface{}) error {
+	var value string
+	switch v := src.(type) {
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	case nil:
+	default:
+		return fmt.Errorf("cannot scan %T into Scopes", src)
+	}
+
+	*s = Scopes{}
+	for _, field := range strings.Fields(value) {
+		*s = append(*s, Scope(field))
+	}
+	return nil
+}
+
+// APIKey is a key for service-to-service integrations. Only a hash of the
+// key is stored, the key itself is shown once when it is created.
+type APIKey struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	Name       string     `json:"name" gorm:"size:255"`
+	Prefix     string     `json:"prefix" gorm:"size:16"` // first characters of the key, to recognise it
+	KeyHash    string     `json:"-" gorm:"size:64;uniqueIndex"`
+	Scopes     Scopes     `json:"scopes" gorm:"size:500"`
+	ExpiresAt  *time.Time `json:"expires_at"` // never expires when nil
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedBy  string     `json:"created_by" gorm:"size:255"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// IsActive reports whether the key is neither revoked nor expired
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// CreateAPIKeyRequest represents the request to create an API key
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"notblank,max=255"`
+	Scopes    []Scope    `json:"scopes" validate:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	CreatedBy string     `json:"-"` // set from the authenticated caller
+}
+
+// CreatedAPIKey is a new API key together with the key itself
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
@@ -0,0 +1,55 @@
+package domain
+
+// Role is the access level of an authenticated caller
+type Role string
+
+// Roles
+const (
+	RoleAdmin    Role = "admin"
+	RoleStaff    Role = "staff"
+	RoleCustomer Role = "customer"
+
+	// RoleService identifies API keys, it cannot be claimed by access tokens
+	RoleService Role = "service"
+)
+
+// IsValid reports whether the role is a known user role
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleAdmin, RoleStaff, RoleCustomer:
+		return true
+	}
+	return false
+}
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	Subject    string // token subject, recorded as the author of changes
+	Role       Role
+	CustomerID uint   // set for customers, who may only see their own data
+	Scopes     Scopes // set for API keys, which are limited to their scopes
+}
+
+// HasRole reports whether the principal has one of the roles
+func (p *Principal) HasRole(roles ...Role) bool {
+	for _, role := range roles {
+		if p.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope reports whether the principal is an API key granted the scope
+func (p *Principal) HasScope(scope Scope) bool {
+	return p.Role == RoleService && p.Scopes.Has(scope)
+}
+
+// CanAccessCustomer reports whether the principal may see the data of a customer.
+// Admins, staff and API keys see every customer, customers only themselves.
+func (p *Principal) CanAccessCustomer(customerID uint) bool {
+	if p.Role == RoleCustomer {
+		return p.CustomerID != 0 && p.CustomerID == customerID
+	}
+	return p.HasRole(RoleAdmin, RoleStaff, RoleService)
+}
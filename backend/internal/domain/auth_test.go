@@ -0,0 +1,60 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+)
+
+func TestPrincipalCanAccessCustomer(t *testing.T) {
+	tests := []struct {
+		name       string
+		principal  domain.Principal
+		customerID uint
+		want       bool
+	}{
+		{"admin", domain.Principal{Role: domain.RoleAdmin}, 1, true},
+		{"staff", domain.Principal{Role: domain.RoleStaff}, 1, true},
+		{"API key", domain.Principal{Role: domain.RoleService}, 1, true},
+		{"customer themselves", domain.Principal{Role: domain.RoleCustomer, CustomerID: 1}, 1, true},
+		{"another customer", domain.Principal{Role: domain.RoleCustomer, CustomerID: 2}, 1, false},
+		{"customer without ID", domain.Principal{Role: domain.RoleCustomer}, 0, false},
+		{"unknown role", domain.Principal{Role: "auditor"}, 1, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.principal.CanAccessCustomer(tt.customerID); got != tt.want {
+			t.Errorf("%s: CanAccessCustomer(%d) = %v, want %v", tt.name, tt.customerID, got, tt.want)
+		}
+	}
+}
+
+func TestPrincipalHasScope(t *testing.T) {
+	apiKey := domain.Principal{Role: domain.RoleService, Scopes: domain.Scopes{domain.ScopeOrdersRead}}
+	if !apiKey.HasScope(domain.ScopeOrdersRead) {
+		t.Errorf("API key lacks its granted scope %s", domain.ScopeOrdersRead)
+	}
+	if apiKey.HasScope(domain.ScopeOrdersWrite) {
+		t.Errorf("API key has scope %s it was not granted", domain.ScopeOrdersWrite)
+	}
+
+	// Scopes only count for API keys
+	user := domain.Principal{Role: domain.RoleStaff, Scopes: domain.Scopes{domain.ScopeOrdersRead}}
+	if user.HasScope(domain.ScopeOrdersRead) {
+		t.Errorf("staff principal has scope %s", domain.ScopeOrdersRead)
+	}
+}
+
+func TestRoleIsValid(t *testing.T) {
+	for _, role := range []domain.Role{domain.RoleAdmin, domain.RoleStaff, domain.RoleCustomer} {
+		if !role.IsValid() {
+			t.Errorf("%s.IsValid() = false, want true", role)
+		}
+	}
+	// Access tokens may not claim the role of API keys
+	for _, role := range []domain.Role{domain.RoleService, "", "root"} {
+		if role.IsValid() {
+			t.Errorf("%q.IsValid() = true, want false", role)
+		}
+	}
+}
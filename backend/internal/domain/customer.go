@@ -0,0 +1,13 @@
+package domain
+
+// CreateCustomerRequest represents the request to create a new customer
+type CreateCustomerRequest struct {
+	Name  string `json:"name" validate:"notblank,max=255"`
+	Email string `json:"email" validate:"required,email,max=255"`
+}
+
+// UpdateCustomerRequest represents the request to update a customer
+type UpdateCustomerRequest struct {
+	Name  string `json:"name" validate:"notblank,max=255"`
+	Email string `json:"email" validate:"required,email,max=255"`
+}
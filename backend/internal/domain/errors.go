@@ -0,0 +1,195 @@
+package domain
+
+import "fmt"
+
+// ErrorKind classifies domain errors, handlers map each kind to an HTTP status
+type ErrorKind string
+
+// Error kinds
+const (
+	KindNotFound          ErrorKind = "not_found"
+	KindConflict          ErrorKind = "conflict"
+	KindValidation        ErrorKind = "validation"
+	KindInsufficientStock ErrorKind = "insufficient_stock"
+	KindUnauthenticated   ErrorKind = "unauthenticated"
+	KindForbidden         ErrorKind = "forbidden"
+	KindRateLimited       ErrorKind = "rate_limited"
+	KindTimeout           ErrorKind = "timeout"
+	KindInternal          ErrorKind = "internal"
+)
+
+// Error is a domain error with a kind, a stable code clients can match on and a
+// message that is safe to show them. Err holds the underlying cause, which is
+// logged but never returned to clients.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	Fields  []FieldError // invalid fields of a validation error
+	Err     error
+}
+
+// FieldError describes why one field of a request is invalid
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Error returns the message followed by the underlying cause, if any
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Unwrap returns the underlying cause
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches domain errors by code, so errors.Is(err, ErrOrderNotFound)
+// also holds for copies made with Withf
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Withf returns a copy of the error with a more specific message
+func (e *Error) Withf(format string, args ...interface{}) *Error {
+	copied := *e
+	copied.Message = fmt.Sprintf(format, args...)
+	return &copied
+}
+
+// WithFields returns a copy of the error listing the invalid fields
+func (e *Error) WithFields(fields []FieldError) *Error {
+	copied := *e
+	copied.Fields = fields
+	return &copied
+}
+
+// NotFound creates an error for a missing resource
+func NotFound(code, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+// Conflict creates an error for a request that clashes with the current state
+func Conflict(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+// Validation creates an error for a well-formed request with invalid values
+func Validation(code, message string) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message}
+}
+
+// Internal wraps an unexpected failure such as a database outage
+func Internal(err error) *Error {
+	return &Error{Kind: KindInternal, Code: "internal_error", Message: "internal error", Err: err}
+}
+
+// Timeout wraps a canceled or expired context as ErrTimeout
+func Timeout(err error) *Error {
+	return &Error{Kind: KindTimeout, Code: ErrTimeout.Code, Message: ErrTimeout.Message, Err: err}
+}
+
+// ErrUnauthenticated is returned when a request lacks valid credentials
+var ErrUnauthenticated = &Error{Kind: KindUnauthenticated, Code: "unauthenticated", Message: "authentication required"}
+
+// ErrInvalidToken is returned for an access token that is malformed, expired or forged
+var ErrInvalidToken = &Error{Kind: KindUnauthenticated, Code: "invalid_token", Message: "invalid or expired token"}
+
+// ErrInvalidCredentials is returned when an email and password do not match an account
+var ErrInvalidCredentials = &Error{Kind: KindUnauthenticated, Code: "invalid_credentials", Message: "invalid email or password"}
+
+// ErrInvalidRefreshToken is returned for a refresh token that is unknown, expired or revoked
+var ErrInvalidRefreshToken = &Error{Kind: KindUnauthenticated, Code: "invalid_refresh_token", Message: "invalid or expired refresh token"}
+
+// ErrInvalidAPIKey is returned for an API key that is unknown, expired or revoked
+var ErrInvalidAPIKey = &Error{Kind: KindUnauthenticated, Code: "invalid_api_key", Message: "invalid or expired API key"}
+
+// ErrForbidden is returned when the caller's role does not allow the request
+var ErrForbidden = &Error{Kind: KindForbidden, Code: "forbidden", Message: "not allowed"}
+
+// ErrRateLimited is returned when a caller exceeds their request budget
+var ErrRateLimited = &Error{Kind: KindRateLimited, Code: "rate_limited", Message: "too many requests, retry later"}
+
+// ErrTimeout is returned when a request runs out of time or is canceled before it completes
+var ErrTimeout = &Error{Kind: KindTimeout, Code: "request_timeout", Message: "the request took too long, retry later"}
+
+// ErrValidation is returned when a request fails validation
+var ErrValidation = Validation("validation_failed", "validation failed")
+
+// ErrProductNotFound is returned when a product does not exist
+var ErrProductNotFound = NotFound("product_not_found", "product not found")
+
+// ErrOrderNotFound is returned when an order does not exist
+var ErrOrderNotFound = NotFound("order_not_found", "order not found")
+
+// ErrCustomerNotFound is returned when a customer does not exist
+var ErrCustomerNotFound = NotFound("customer_not_found", "customer not found")
+
+// ErrCredentialNotFound is returned when a customer has no password set
+var ErrCredentialNotFound = NotFound("credential_not_found", "credential not found")
+
+// ErrRefreshTokenNotFound is returned when a refresh token is not stored
+var ErrRefreshTokenNotFound = NotFound("refresh_token_not_found", "refresh token not found")
+
+// ErrResetTokenNotFound is returned when a password reset token is not stored
+var ErrResetTokenNotFound = NotFound("reset_token_not_found", "password reset token not found")
+
+// ErrAPIKeyNotFound is returned when an API key does not exist
+var ErrAPIKeyNotFound = NotFound("api_key_not_found", "API key not found")
+
+// ErrUnknownCustomer is returned when an order references a customer that does not exist
+var ErrUnknownCustomer = Validation("unknown_customer", "customer does not exist")
+
+// ErrUnknownProduct is returned when an order references a product that does not exist
+var ErrUnknownProduct = Validation("unknown_product", "product does not exist")
+
+// ErrInsufficientStock is returned when a product cannot cover the requested quantity
+var ErrInsufficientStock = &Error{Kind: KindInsufficientStock, Code: "insufficient_stock", Message: "insufficient stock"}
+
+// ErrInvalidStatus is returned for an unknown order status
+var ErrInvalidStatus = Validation("invalid_status", "invalid status")
+
+// ErrInvalidTransition is returned when the order lifecycle forbids a status change
+var ErrInvalidTransition = Conflict("invalid_status_transition", "invalid status transition")
+
+// ErrEmailTaken is returned when another customer already uses the email address
+var ErrEmailTaken = Conflict("email_taken", "email already in use")
+
+// ErrCustomerHasOrders is returned when deleting a customer that still has orders
+var ErrCustomerHasOrders = Conflict("customer_has_orders", "customer has orders and cannot be deleted")
+
+// ErrConflict is returned when another request changed a record first
+var ErrConflict = Conflict("concurrent_update", "the resource was changed by another request, reload and retry")
+
+// ErrDuplicate is returned when a write violates a unique constraint
+var ErrDuplicate = Conflict("duplicate", "a record with the same unique value already exists")
+
+// ErrInUse is returned when deleting a record that other records still reference
+var ErrInUse = Conflict("resource_in_use", "resource is referenced by other records")
+
+// ErrUnsupportedCurrency is returned for a currency code without known minor units
+var ErrUnsupportedCurrency = Validation("unsupported_currency", "unsupported currency")
+
+// ErrAmountPrecision is returned for an amount with more decimals than its currency has minor units
+var ErrAmountPrecision = Validation("invalid_amount_precision", "amount has more decimals than the currency allows")
+
+// ErrAmountOutOfRange is returned when a sum or product of amounts does not fit in int64 minor units
+var ErrAmountOutOfRange = Validation("amount_out_of_range", "amount is out of range")
+
+// ErrCurrencyMismatch is returned when combining amounts in different currencies
+var ErrCurrencyMismatch = Validation("currency_mismatch", "currency mismatch")
+
+// ErrInvalidResetToken is returned for a password reset token that is unknown, used or expired
+var ErrInvalidResetToken = Validation("invalid_reset_token", "invalid or expired password reset token")
+
+// ErrInvalidScope is returned for an unknown API key scope
+var ErrInvalidScope = Validation("invalid_scope", "invalid scope")
+
+// ErrInvalidCursor is returned for a malformed page cursor or one issued for another sort
+var ErrInvalidCursor = Validation("invalid_cursor", "invalid cursor")
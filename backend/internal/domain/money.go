@@ -0,0 +1,179 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is used when an amount is given without a currency
+const DefaultCurrency = "USD"
+
+// currencyExponents maps supported ISO 4217 codes to their number of minor unit digits
+var currencyExponents = map[string]int{
+	"AUD": 2, "CAD": 2, "CHF": 2, "CNY": 2, "EUR": 2, "GBP": 2, "HKD": 2,
+	"INR": 2, "SGD": 2, "THB": 2, "USD": 2,
+	"JPY": 0, "KRW": 0, "VND": 0,
+	"BHD": 3, "KWD": 3,
+}
+
+// decimalPattern matches a plain decimal number such as 29.99 or -0.5
+var decimalPattern = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+// Money is an exact amount stored as an integer count of the currency's minor
+// unit (cents for USD), so arithmetic never drifts like float64 does.
+//
+// Amounts are never rounded: parsing rejects more decimals than the currency
+// allows (29.995 USD) but accepts trailing zeros (29.990 USD). Addition and
+// multiplication by a quantity are exact, or fail with ErrAmountOutOfRange
+// rather than wrap around.
+type Money struct {
+	Amount   int64  `gorm:"not null;default:0"`
+	Currency string `gorm:"size:3;not null"`
+}
+
+// NewMoney creates money from an amount in minor units
+func NewMoney(minor int64, currency string) (Money, error) {
+	currency = strings.ToUpper(currency)
+	if _, ok := currencyExponents[currency]; !ok {
+		return Money{}, ErrUnsupportedCurrency.Withf("unsupported currency %q", currency)
+	}
+	return Money{Amount: minor, Currency: currency}, nil
+}
+
+// ParseMoney parses a decimal amount such as "29.99" in the given currency.
+// It returns ErrAmountPrecision rather than rounding excess decimals.
+func ParseMoney(amount, currency string) (Money, error) {
+	currency = strings.ToUpper(currency)
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	exponent, ok := currencyExponents[currency]
+	if !ok {
+		return Money{}, ErrUnsupportedCurrency.Withf("unsupported currency %q", currency)
+	}
+
+	amount = strings.TrimSpace(amount)
+	if !decimalPattern.MatchString(amount) {
+		return Money{}, fmt.Errorf("invalid amount %q", amount)
+	}
+
+	value, ok := new(big.Rat).SetString(amount)
+	if !ok {
+		return Money{}, fmt.Errorf("invalid amount %q", amount)
+	}
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil))
+	value.Mul(value, scale)
+	if !value.IsInt() {
+		return Money{}, ErrAmountPrecision.Withf("amount %q has more than %d decimals for %s", amount, exponent, currency)
+	}
+	minor := value.Num()
+	if !minor.IsInt64() {
+		return Money{}, fmt.Errorf("amount %q is out of range", amount)
+	}
+
+	return Money{Amount: minor.Int64(), Currency: currency}, nil
+}
+
+// MustParseMoney is like ParseMoney but panics on error, for literals in code
+func MustParseMoney(amount, currency string) Money {
+	money, err := ParseMoney(amount, currency)
+	if err != nil {
+		panic(err)
+	}
+	return money
+}
+
+// Add returns the sum of two amounts in the same currency
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch.Withf("currency mismatch: %s and %s", m.Currency, other.Currency)
+	}
+	sum := m.Amount + other.Amount
+	if (other.Amount > 0 && sum < m.Amount) || (other.Amount < 0 && sum > m.Amount) {
+		return Money{}, ErrAmountOutOfRange.Withf("%s plus %s is out of range", m, other)
+	}
+	return Money{Amount: sum, Currency: m.Currency}, nil
+}
+
+// Mul returns the amount multiplied by a quantity
+func (m Money) Mul(quantity int) (Money, error) {
+	product := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(int64(quantity)))
+	if !product.IsInt64() {
+		return Money{}, ErrAmountOutOfRange.Withf("%s times %d is out of range", m, quantity)
+	}
+	return Money{Amount: product.Int64(), Currency: m.Currency}, nil
+}
+
+// IsNegative reports whether the amount is below zero
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Decimal formats the amount in major units, e.g. "29.99"
+func (m Money) Decimal() string {
+	exponent := currencyExponents[m.Currency]
+	if exponent == 0 {
+		return strconv.FormatInt(m.Amount, 10)
+	}
+
+	sign := ""
+	minor := m.Amount
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+	digits := fmt.Sprintf("%0*d", exponent+1, minor)
+	return sign + digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+}
+
+// String formats the amount with its currency, e.g. "29.99 USD"
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+// moneyJSON is the wire format of Money
+type moneyJSON struct {
+	Amount   json.RawMessage `json:"amount"`
+	Currency string          `json:"currency"`
+}
+
+// MarshalJSON encodes money as {"amount":"29.99","currency":"USD"}.
+// The amount is a string so clients never parse it into a float.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{m.Decimal(), m.Currency})
+}
+
+// UnmarshalJSON accepts {"amount":"29.99","currency":"USD"} with the amount as
+// a string or number, or a bare amount in the default currency. Numbers are read
+// from their decimal text and never pass through float64.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	var wire moneyJSON
+	if len(data) > 0 && data[0] == '{' {
+		if err := json.Unmarshal(data, &wire); err != nil {
+			return err
+		}
+	} else {
+		wire.Amount = data
+	}
+
+	amount := strings.Trim(string(wire.Amount), `"`)
+	parsed, err := ParseMoney(amount, wire.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
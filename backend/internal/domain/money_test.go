@@ -0,0 +1,190 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		want     domain.Money
+	}{
+		{"29.99", "USD", domain.Money{Amount: 2999, Currency: "USD"}},
+		{"0.5", "USD", domain.Money{Amount: 50, Currency: "USD"}},
+		{"-0.05", "USD", domain.Money{Amount: -5, Currency: "USD"}},
+		{" 12 ", "usd", domain.Money{Amount: 1200, Currency: "USD"}},
+		{"1.500", "USD", domain.Money{Amount: 150, Currency: "USD"}},
+		{"7", "", domain.Money{Amount: 700, Currency: domain.DefaultCurrency}},
+		{"1000", "JPY", domain.Money{Amount: 1000, Currency: "JPY"}},
+		{"1000.0", "JPY", domain.Money{Amount: 1000, Currency: "JPY"}},
+		{"1.234", "KWD", domain.Money{Amount: 1234, Currency: "KWD"}},
+	}
+
+	for _, tt := range tests {
+		got, err := domain.ParseMoney(tt.amount, tt.currency)
+		if err != nil {
+			t.Errorf("ParseMoney(%q, %q) failed: %v", tt.amount, tt.currency, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMoney(%q, %q) = %+v, want %+v", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestParseMoneyRejectsInvalidAmounts(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		want     error // nil for a plain parse error
+	}{
+		{"1.005", "USD", domain.ErrAmountPrecision},
+		{"29.995", "USD", domain.ErrAmountPrecision},
+		{"0.001", "USD", domain.ErrAmountPrecision},
+		{"1.5", "JPY", domain.ErrAmountPrecision},
+		{"1.2345", "KWD", domain.ErrAmountPrecision},
+		{"1.00", "XYZ", domain.ErrUnsupportedCurrency},
+		{"", "USD", nil},
+		{"abc", "USD", nil},
+		{"1e3", "USD", nil},
+		{"1.", "USD", nil},
+		{"99999999999999999999", "USD", nil},
+	}
+
+	for _, tt := range tests {
+		_, err := domain.ParseMoney(tt.amount, tt.currency)
+		if err == nil {
+			t.Errorf("ParseMoney(%q, %q) succeeded, want an error", tt.amount, tt.currency)
+			continue
+		}
+		if tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("ParseMoney(%q, %q) = %v, want %v", tt.amount, tt.currency, err, tt.want)
+		}
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	price := domain.MustParseMoney("0.10", "USD")
+
+	// Ten times 0.10 is exactly 1.00, which float64 cannot promise
+	total := domain.Money{Currency: "USD"}
+	for i := 0; i < 10; i++ {
+		var err error
+		if total, err = total.Add(price); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+	if want := domain.MustParseMoney("1.00", "USD"); total != want {
+		t.Errorf("sum of ten 0.10 = %v, want %v", total, want)
+	}
+	if got, err := price.Mul(3); err != nil || got != domain.MustParseMoney("0.30", "USD") {
+		t.Errorf("0.10 * 3 = %v, %v, want 0.30 USD", got, err)
+	}
+
+	if _, err := price.Add(domain.MustParseMoney("1", "EUR")); !errors.Is(err, domain.ErrCurrencyMismatch) {
+		t.Errorf("adding EUR to USD = %v, want %v", err, domain.ErrCurrencyMismatch)
+	}
+}
+
+func TestMoneyOverflow(t *testing.T) {
+	largest := domain.Money{Amount: math.MaxInt64, Currency: "USD"}
+	smallest := domain.Money{Amount: math.MinInt64, Currency: "USD"}
+	cent := domain.Money{Amount: 1, Currency: "USD"}
+	minusCent := domain.Money{Amount: -1, Currency: "USD"}
+
+	sums := []struct {
+		a, b domain.Money
+	}{
+		{largest, cent},
+		{cent, largest},
+		{smallest, minusCent},
+	}
+	for _, tt := range sums {
+		if got, err := tt.a.Add(tt.b); !errors.Is(err, domain.ErrAmountOutOfRange) {
+			t.Errorf("%v + %v = %v, %v, want %v", tt.a, tt.b, got, err, domain.ErrAmountOutOfRange)
+		}
+	}
+	if got, err := largest.Add(minusCent); err != nil || got.Amount != math.MaxInt64-1 {
+		t.Errorf("largest - 0.01 = %v, %v, want %d minor units", got, err, int64(math.MaxInt64-1))
+	}
+
+	products := []struct {
+		money    domain.Money
+		quantity int
+	}{
+		{domain.MustParseMoney("92233720368547758.07", "USD"), 2},
+		{domain.MustParseMoney("1000000", "USD"), 1 << 40},
+		{smallest, -1},
+		{minusCent, math.MinInt64},
+	}
+	for _, tt := range products {
+		if got, err := tt.money.Mul(tt.quantity); !errors.Is(err, domain.ErrAmountOutOfRange) {
+			t.Errorf("%v * %d = %v, %v, want %v", tt.money, tt.quantity, got, err, domain.ErrAmountOutOfRange)
+		}
+	}
+	if got, err := domain.MustParseMoney("46116860184273879.03", "USD").Mul(2); err != nil || got.Amount != math.MaxInt64-1 {
+		t.Errorf("largest even product = %v, %v, want %d minor units", got, err, int64(math.MaxInt64-1))
+	}
+}
+
+func TestMoneyFormat(t *testing.T) {
+	tests := []struct {
+		money domain.Money
+		want  string
+	}{
+		{domain.Money{Amount: 2999, Currency: "USD"}, "29.99 USD"},
+		{domain.Money{Amount: 5, Currency: "USD"}, "0.05 USD"},
+		{domain.Money{Amount: -5, Currency: "USD"}, "-0.05 USD"},
+		{domain.Money{Amount: 0, Currency: "USD"}, "0.00 USD"},
+		{domain.Money{Amount: 1000, Currency: "JPY"}, "1000 JPY"},
+		{domain.Money{Amount: 1234, Currency: "KWD"}, "1.234 KWD"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.money, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	data, err := json.Marshal(domain.Money{Amount: 99999, Currency: "USD"})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if want := `{"amount":"999.99","currency":"USD"}`; string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+
+	tests := []struct {
+		json string
+		want domain.Money
+	}{
+		{`{"amount":"999.99","currency":"USD"}`, domain.Money{Amount: 99999, Currency: "USD"}},
+		{`{"amount":999.99,"currency":"usd"}`, domain.Money{Amount: 99999, Currency: "USD"}},
+		{`{"amount":"500","currency":"JPY"}`, domain.Money{Amount: 500, Currency: "JPY"}},
+		{`"12.50"`, domain.Money{Amount: 1250, Currency: domain.DefaultCurrency}},
+		{`0.1`, domain.Money{Amount: 10, Currency: domain.DefaultCurrency}},
+	}
+	for _, tt := range tests {
+		var got domain.Money
+		if err := json.Unmarshal([]byte(tt.json), &got); err != nil {
+			t.Errorf("Unmarshal(%s) failed: %v", tt.json, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Unmarshal(%s) = %+v, want %+v", tt.json, got, tt.want)
+		}
+	}
+
+	var got domain.Money
+	if err := json.Unmarshal([]byte(`{"amount":1.005,"currency":"USD"}`), &got); !errors.Is(err, domain.ErrAmountPrecision) {
+		t.Errorf("Unmarshal of 1.005 USD = %v, want %v", err, domain.ErrAmountPrecision)
+	}
+}
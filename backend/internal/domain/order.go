@@ -4,14 +4,14 @@ import "time"
 
 // Order represents a shop order entity
 type Order struct {
-	ID         uint      `json:"id" gorm:"primaryKey"`
-	CustomerID uint      `json:"customer_id"`
-	Customer   Customer  `json:"customer" gorm:"foreignKey:CustomerID"`
+	ID         uint        `json:"id" gorm:"primaryKey"`
+	CustomerID uint        `json:"customer_id"`
+	Customer   Customer    `json:"customer" gorm:"foreignKey:CustomerID"`
 	Items      []OrderItem `json:"items" gorm:"foreignKey:OrderID"`
-	Total      float64   `json:"total"`
-	Status     string    `json:"status"` // pending, processing, completed, cancelled
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	Total      Money       `json:"total" gorm:"embedded;embeddedPrefix:total_"`
+	Status     OrderStatus `json:"status" gorm:"size:50"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
 }
 
 // OrderItem represents an item in an order
@@ -21,7 +21,7 @@ type OrderItem struct {
 	ProductID uint    `json:"product_id"`
 	Product   Product `json:"product" gorm:"foreignKey:ProductID"`
 	Quantity  int     `json:"quantity"`
-	Price     float64 `json:"price"`
+	Price     Money   `json:"price" gorm:"embedded;embeddedPrefix:price_"`
 }
 
 // Customer represents a customer entity
@@ -38,7 +38,7 @@ type Product struct {
 	ID          uint      `json:"id" gorm:"primaryKey"`
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
-	Price       float64   `json:"price"`
+	Price       Money     `json:"price" gorm:"embedded;embeddedPrefix:price_"`
 	Stock       int       `json:"stock"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
@@ -46,8 +46,9 @@ type Product struct {
 
 // CreateOrderRequest represents the request to create a new order
 type CreateOrderRequest struct {
-	CustomerID uint              `json:"customer_id" validate:"required"`
-	Items      []OrderItemRequest `json:"items" validate:"required,min=1"`
+	CustomerID uint               `json:"customer_id" validate:"required"`
+	Items      []OrderItemRequest `json:"items" validate:"required,min=1,dive"`
+	CreatedBy  string             `json:"-"` // set from the authenticated caller
 }
 
 // OrderItemRequest represents an item in the order request
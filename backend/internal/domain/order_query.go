@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// OrderSortField is a whitelisted field orders can be sorted by
+type OrderSortField string
+
+// Order sort fields
+const (
+	OrderSortCreatedAt OrderSortField = "created_at"
+	OrderSortTotal     OrderSortField = "total"
+	OrderSortStatus    OrderSortField = "status"
+)
+
+// IsValid reports whether orders can be sorted by the field
+func (f OrderSortField) IsValid() bool {
+	switch f {
+	case OrderSortCreatedAt, OrderSortTotal, OrderSortStatus:
+		return true
+	}
+	return false
+}
+
+// OrderQuery describes the filters, sorting and page of an order listing
+type OrderQuery struct {
+	Status        OrderStatus
+	CustomerID    uint
+	CustomerEmail string
+	ProductID     uint // only orders containing this product
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Currency      string // currency of MinTotal and MaxTotal, orders in other currencies are excluded
+	MinTotal      *Money
+	MaxTotal      *Money
+	SortBy        OrderSortField
+	SortDesc      bool
+	PageRequest
+}
@@ -0,0 +1,89 @@
+package domain
+
+import "time"
+
+// OrderStatus is the lifecycle state of an order
+type OrderStatus string
+
+// Order lifecycle states
+const (
+	OrderStatusPending    OrderStatus = "pending"
+	OrderStatusProcessing OrderStatus = "processing"
+	OrderStatusCompleted  OrderStatus = "completed"
+	OrderStatusCancelled  OrderStatus = "cancelled"
+)
+
+// orderTransitions lists the statuses each status may move to.
+// Completed and cancelled are final.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending:    {OrderStatusProcessing, OrderStatusCancelled},
+	OrderStatusProcessing: {OrderStatusCompleted, OrderStatusCancelled},
+	OrderStatusCompleted:  {},
+	OrderStatusCancelled:  {},
+}
+
+// IsValid reports whether the status is a known lifecycle state
+func (s OrderStatus) IsValid() bool {
+	_, ok := orderTransitions[s]
+	return ok
+}
+
+// IsFinal reports whether no further transitions are allowed
+func (s OrderStatus) IsFinal() bool {
+	return s.IsValid() && len(orderTransitions[s]) == 0
+}
+
+// CanTransitionTo reports whether moving to next is allowed
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ReleasesStock reports whether entering the status returns reserved stock
+func (s OrderStatus) ReleasesStock() bool {
+	return s == OrderStatusCancelled
+}
+
+// TransitionTo moves the order to next after checking the lifecycle rules and guards
+func (o *Order) TransitionTo(next OrderStatus, reason string) error {
+	if !next.IsValid() {
+		return ErrInvalidStatus.Withf("invalid status %q", next)
+	}
+	if !o.Status.CanTransitionTo(next) {
+		return ErrInvalidTransition.Withf("cannot change order from %s to %s", o.Status, next)
+	}
+	if next == OrderStatusCancelled && reason == "" {
+		return ErrInvalidTransition.Withf("a reason is required to cancel an order")
+	}
+
+	o.Status = next
+	o.UpdatedAt = time.Now()
+	return nil
+}
+
+// OrderStatusHistory records a single status change of an order
+type OrderStatusHistory struct {
+	ID         uint        `json:"id" gorm:"primaryKey"`
+	OrderID    uint        `json:"order_id" gorm:"index"`
+	FromStatus OrderStatus `json:"from_status"` // empty for the initial status
+	ToStatus   OrderStatus `json:"to_status"`
+	ChangedBy  string      `json:"changed_by"`
+	Reason     string      `json:"reason"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// TableName overrides the table name used by OrderStatusHistory
+func (OrderStatusHistory) TableName() string {
+	return "order_status_history"
+}
+
+// UpdateOrderStatusRequest represents the request to change an order status
+type UpdateOrderStatusRequest struct {
+	Status    OrderStatus `json:"status" validate:"required"`
+	Reason    string      `json:"reason"`
+	ChangedBy string      `json:"-"` // set from the authenticated caller
+}
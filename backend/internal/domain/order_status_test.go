@@ -0,0 +1,94 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+)
+
+func TestOrderTransitionTo(t *testing.T) {
+	const (
+		pending    = domain.OrderStatusPending
+		processing = domain.OrderStatusProcessing
+		completed  = domain.OrderStatusCompleted
+		cancelled  = domain.OrderStatusCancelled
+	)
+
+	tests := []struct {
+		from, to domain.OrderStatus
+		reason   string
+		want     error
+	}{
+		// Allowed transitions
+		{pending, processing, "", nil},
+		{pending, cancelled, "customer request", nil},
+		{processing, completed, "", nil},
+		{processing, cancelled, "out of stock", nil},
+
+		// Skipping or reversing a step
+		{pending, completed, "", domain.ErrInvalidTransition},
+		{processing, pending, "", domain.ErrInvalidTransition},
+		{pending, pending, "", domain.ErrInvalidTransition},
+
+		// Final states cannot be left
+		{completed, pending, "", domain.ErrInvalidTransition},
+		{completed, processing, "", domain.ErrInvalidTransition},
+		{completed, cancelled, "refund", domain.ErrInvalidTransition},
+		{cancelled, pending, "", domain.ErrInvalidTransition},
+		{cancelled, processing, "", domain.ErrInvalidTransition},
+		{cancelled, completed, "", domain.ErrInvalidTransition},
+		{cancelled, cancelled, "again", domain.ErrInvalidTransition},
+
+		// Cancelling needs a reason
+		{pending, cancelled, "", domain.ErrInvalidTransition},
+		{processing, cancelled, "", domain.ErrInvalidTransition},
+
+		// Unknown statuses
+		{pending, "shipped", "", domain.ErrInvalidStatus},
+		{pending, "", "", domain.ErrInvalidStatus},
+		{"shipped", processing, "", domain.ErrInvalidTransition},
+	}
+
+	for _, tt := range tests {
+		order := domain.Order{Status: tt.from}
+		err := order.TransitionTo(tt.to, tt.reason)
+
+		switch {
+		case tt.want == nil && err != nil:
+			t.Errorf("%s -> %s: TransitionTo failed: %v", tt.from, tt.to, err)
+		case tt.want == nil && (order.Status != tt.to || order.UpdatedAt.IsZero()):
+			t.Errorf("%s -> %s: got status %s updated at %v", tt.from, tt.to, order.Status, order.UpdatedAt)
+		case tt.want != nil && !errors.Is(err, tt.want):
+			t.Errorf("%s -> %s (reason %q): TransitionTo = %v, want %v", tt.from, tt.to, tt.reason, err, tt.want)
+		case tt.want != nil && order.Status != tt.from:
+			t.Errorf("%s -> %s: rejected transition changed the status to %s", tt.from, tt.to, order.Status)
+		}
+	}
+}
+
+func TestOrderStatusProperties(t *testing.T) {
+	tests := []struct {
+		status        domain.OrderStatus
+		valid, final  bool
+		releasesStock bool
+	}{
+		{domain.OrderStatusPending, true, false, false},
+		{domain.OrderStatusProcessing, true, false, false},
+		{domain.OrderStatusCompleted, true, true, false},
+		{domain.OrderStatusCancelled, true, true, true},
+		{"shipped", false, false, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.status.IsValid(); got != tt.valid {
+			t.Errorf("%q.IsValid() = %v, want %v", tt.status, got, tt.valid)
+		}
+		if got := tt.status.IsFinal(); got != tt.final {
+			t.Errorf("%q.IsFinal() = %v, want %v", tt.status, got, tt.final)
+		}
+		if got := tt.status.ReleasesStock(); got != tt.releasesStock {
+			t.Errorf("%q.ReleasesStock() = %v, want %v", tt.status, got, tt.releasesStock)
+		}
+	}
+}
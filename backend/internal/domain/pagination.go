@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// DefaultPageLimit is the page size used when a listing does not ask for one
+const DefaultPageLimit = 10
+
+// PageRequest selects one page of a listing, either by offset or by cursor
+type PageRequest struct {
+	Limit  int
+	Offset int     // ignored when Cursor is set
+	Cursor *Cursor // continue after (or before) the row the cursor points at
+}
+
+// Clamp applies the default page size and caps it at maxLimit
+func (p *PageRequest) Clamp(maxLimit int) {
+	if p.Limit <= 0 {
+		p.Limit = DefaultPageLimit
+	}
+	if maxLimit > 0 && p.Limit > maxLimit {
+		p.Limit = maxLimit
+	}
+	if p.Offset < 0 || p.Cursor != nil {
+		p.Offset = 0
+	}
+}
+
+// Cursor points at a row of a sorted listing for keyset pagination. It is only
+// valid for the sort it was issued for, which is recorded in Sort.
+type Cursor struct {
+	Sort     string `json:"s"`           // sort column and direction, e.g. "price_amount:desc"
+	Value    string `json:"v,omitempty"` // sort column value of the row
+	ID       uint   `json:"id"`          // ID of the row, breaks ties between equal values
+	Backward bool   `json:"b,omitempty"` // page towards the start of the listing
+}
+
+// Encode returns the cursor as an opaque URL-safe token
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a token created by Cursor.Encode
+func DecodeCursor(token string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Sort == "" || cursor.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// Page is one page of a listing together with the cursors of its neighbours
+type Page[T any] struct {
+	Items []T
+	Total int64 // rows matching the filters across all pages
+	Limit int
+	Next  *Cursor // nil on the last page
+	Prev  *Cursor // nil on the first page
+}
+
+// MapPage converts the items of a page, keeping its totals and cursors
+func MapPage[T, R any](page *Page[T], convert func(item *T) R) *Page[R] {
+	items := make([]R, len(page.Items))
+	for i := range page.Items {
+		items[i] = convert(&page.Items[i])
+	}
+	return &Page[R]{Items: items, Total: page.Total, Limit: page.Limit, Next: page.Next, Prev: page.Prev}
+}
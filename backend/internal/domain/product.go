@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+// CreateProductRequest represents the request to create a new product
+type CreateProductRequest struct {
+	Name        string `json:"name" validate:"notblank,max=255"`
+	Description string `json:"description"`
+	Price       Money  `json:"price" validate:"required,nonnegative"`
+	Stock       int    `json:"stock" validate:"min=0"`
+}
+
+// UpdateProductRequest represents the request to replace all editable fields of a product
+type UpdateProductRequest struct {
+	Name        string `json:"name" validate:"notblank,max=255"`
+	Description string `json:"description"`
+	Price       Money  `json:"price" validate:"required,nonnegative"`
+	Stock       int    `json:"stock" validate:"min=0"`
+}
+
+// PatchProductRequest represents the request to change some fields of a product.
+// Fields left out of the body (or null) keep their current value.
+type PatchProductRequest struct {
+	Name        *string `json:"name" validate:"omitnil,notblank,max=255"`
+	Description *string `json:"description"`
+	Price       *Money  `json:"price" validate:"omitnil,nonnegative"`
+	Stock       *int    `json:"stock" validate:"omitnil,min=0"`
+}
+
+// Patch returns the update as a patch that sets every field
+func (r *UpdateProductRequest) Patch() *PatchProductRequest {
+	return &PatchProductRequest{
+		Name:        &r.Name,
+		Description: &r.Description,
+		Price:       &r.Price,
+		Stock:       &r.Stock,
+	}
+}
+
+// ProductResponse is the API representation of a product
+type ProductResponse struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Price       Money     `json:"price"`
+	Stock       int       `json:"stock"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NewProductResponse maps a product to its API representation
+func NewProductResponse(product *Product) ProductResponse {
+	return ProductResponse{
+		ID:          product.ID,
+		Name:        product.Name,
+		Description: product.Description,
+		Price:       product.Price,
+		Stock:       product.Stock,
+		CreatedAt:   product.CreatedAt,
+		UpdatedAt:   product.UpdatedAt,
+	}
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// ProductSortField is a whitelisted field products can be sorted by
+type ProductSortField string
+
+// Product sort fields
+const (
+	ProductSortCreatedAt ProductSortField = "created_at"
+	ProductSortName      ProductSortField = "name"
+	ProductSortPrice     ProductSortField = "price"
+)
+
+// IsValid reports whether products can be sorted by the field
+func (f ProductSortField) IsValid() bool {
+	switch f {
+	case ProductSortCreatedAt, ProductSortName, ProductSortPrice:
+		return true
+	}
+	return false
+}
+
+// MaxSearchLength caps the search text to keep LIKE patterns cheap
+const MaxSearchLength = 100
+
+// ProductQuery describes the filters, sorting and page of a product listing
+type ProductQuery struct {
+	Search       string // words that must all appear in the name or description
+	Currency     string // currency of MinPrice and MaxPrice, products in other currencies are excluded
+	MinPrice     *Money
+	MaxPrice     *Money
+	InStock      bool
+	CreatedAfter *time.Time
+	SortBy       ProductSortField
+	SortDesc     bool
+	PageRequest
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+	"github.com/modmastei2/Go-next/backend/internal/middleware"
+	"github.com/modmastei2/Go-next/backend/internal/usecase"
+)
+
+// APIKeyHandler handles HTTP requests for API keys
+type APIKeyHandler struct {
+	apiKeyUsecase usecase.APIKeyUsecase
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(apiKeyUsecase usecase.APIKeyUsecase) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyUsecase: apiKeyUsecase,
+	}
+}
+
+// CreateAPIKey handles POST /api/api-keys
+func (h *APIKeyHandler) CreateAPIKey(c *fiber.Ctx) error {
+	var req domain.CreateAPIKeyRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+	if principal := middleware.PrincipalFrom(c); principal != nil {
+		req.CreatedBy = principal.Subject
+	}
+
+	apiKey, err := h.apiKeyUsecase.CreateAPIKey(c.UserContext(), &req)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "API key created successfully, store the key now as it cannot be shown again",
+		"data":    apiKey,
+	})
+}
+
+// GetAPIKeys handles GET /api/api-keys
+func (h *APIKeyHandler) GetAPIKeys(c *fiber.Ctx) error {
+	apiKeys, err := h.apiKeyUsecase.GetAPIKeys(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"data": apiKeys,
+	})
+}
+
+// RevokeAPIKey handles DELETE /api/api-keys/:id
+func (h *APIKeyHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid API key ID")
+	}
+
+	if err := h.apiKeyUsecase.RevokeAPIKey(c.UserContext(), uint(id)); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "API key revoked successfully",
+	})
+}
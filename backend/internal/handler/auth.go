@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+	"github.com/modmastei2/Go-next/backend/internal/middleware"
+)
+
+// canAccessCustomer reports whether the caller may see the data of a customer
+func canAccessCustomer(c *fiber.Ctx, customerID uint) bool {
+	principal := middleware.PrincipalFrom(c)
+	return principal != nil && principal.CanAccessCustomer(customerID)
+}
+
+// ownCustomerID returns the caller's customer ID when the caller is a customer
+func ownCustomerID(c *fiber.Ctx) (uint, bool) {
+	principal := middleware.PrincipalFrom(c)
+	if principal == nil || principal.Role != domain.RoleCustomer {
+		return 0, false
+	}
+	return principal.CustomerID, true
+}
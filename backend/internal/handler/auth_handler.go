@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+	"github.com/modmastei2/Go-next/backend/internal/usecase"
+)
+
+// AuthHandler handles HTTP requests for customer accounts
+type AuthHandler struct {
+	authUsecase usecase.AuthUsecase
+}
+
+// NewAuthHandler creates a new auth handler
+func NewAuthHandler(authUsecase usecase.AuthUsecase) *AuthHandler {
+	return &AuthHandler{
+		authUsecase: authUsecase,
+	}
+}
+
+// Register handles POST /api/auth/register
+func (h *AuthHandler) Register(c *fiber.Ctx) error {
+	var req domain.RegisterRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	customer, err := h.authUsecase.Register(c.UserContext(), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Account created successfully",
+		"data":    customer,
+	})
+}
+
+// Login handles POST /api/auth/login
+func (h *AuthHandler) Login(c *fiber.Ctx) error {
+	var req domain.LoginRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	tokens, err := h.authUsecase.Login(c.UserContext(), &req)
+	if err != nil {
+		return err
+	}
+
+	return respondTokens(c, tokens)
+}
+
+// Refresh handles POST /api/auth/refresh
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	var req domain.RefreshTokenRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	tokens, err := h.authUsecase.Refresh(c.UserContext(), req.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	return respondTokens(c, tokens)
+}
+
+// Logout handles POST /api/auth/logout
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	var req domain.RefreshTokenRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.authUsecase.Logout(c.UserContext(), req.RefreshToken); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Logged out successfully",
+	})
+}
+
+// RequestPasswordReset handles POST /api/auth/password-reset
+func (h *AuthHandler) RequestPasswordReset(c *fiber.Ctx) error {
+	var req domain.PasswordResetRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.authUsecase.RequestPasswordReset(c.UserContext(), &req); err != nil {
+		return err
+	}
+
+	// The same answer for unknown emails, so accounts cannot be discovered
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "If the email belongs to an account, a reset token has been sent",
+	})
+}
+
+// ResetPassword handles POST /api/auth/password-reset/confirm
+func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
+	var req domain.ConfirmPasswordResetRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.authUsecase.ResetPassword(c.UserContext(), &req); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Password reset successfully",
+	})
+}
+
+// respondTokens writes issued tokens, which must never be cached
+func respondTokens(c *fiber.Ctx, tokens *domain.TokenResponse) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(fiber.Map{
+		"data": tokens,
+	})
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+	"github.com/modmastei2/Go-next/backend/internal/usecase"
+)
+
+// CustomerHandler handles HTTP requests for customers
+type CustomerHandler struct {
+	customerUsecase usecase.CustomerUsecase
+}
+
+// NewCustomerHandler creates a new customer handler
+func NewCustomerHandler(customerUsecase usecase.CustomerUsecase) *CustomerHandler {
+	return &CustomerHandler{
+		customerUsecase: customerUsecase,
+	}
+}
+
+// CreateCustomer handles POST /api/customers
+func (h *CustomerHandler) CreateCustomer(c *fiber.Ctx) error {
+	var req domain.CreateCustomerRequest
+
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	customer, err := h.customerUsecase.CreateCustomer(c.UserContext(), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Customer created successfully",
+		"data":    customer,
+	})
+}
+
+// GetCustomer handles GET /api/customers/:id
+func (h *CustomerHandler) GetCustomer(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid customer ID")
+	}
+	if !canAccessCustomer(c, uint(id)) {
+		return domain.ErrCustomerNotFound
+	}
+
+	customer, err := h.customerUsecase.GetCustomer(c.UserContext(), uint(id))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"data": customer,
+	})
+}
+
+// GetCustomerByEmail handles GET /api/customers/lookup?email=
+func (h *CustomerHandler) GetCustomerByEmail(c *fiber.Ctx) error {
+	email := c.Query("email")
+	if email == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "Email is required")
+	}
+
+	customer, err := h.customerUsecase.GetCustomerByEmail(c.UserContext(), email)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"data": customer,
+	})
+}
+
+// GetCustomers handles GET /api/customers
+func (h *CustomerHandler) GetCustomers(c *fiber.Ctx) error {
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return err
+	}
+
+	customers, err := h.customerUsecase.GetCustomers(c.UserContext(), page)
+	if err != nil {
+		return err
+	}
+
+	return respondPage(c, customers)
+}
+
+// UpdateCustomer handles PUT /api/customers/:id
+func (h *CustomerHandler) UpdateCustomer(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid customer ID")
+	}
+
+	var req domain.UpdateCustomerRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	customer, err := h.customerUsecase.UpdateCustomer(c.UserContext(), uint(id), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Customer updated successfully",
+		"data":    customer,
+	})
+}
+
+// DeleteCustomer handles DELETE /api/customers/:id
+func (h *CustomerHandler) DeleteCustomer(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid customer ID")
+	}
+
+	if err := h.customerUsecase.DeleteCustomer(c.UserContext(), uint(id)); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Customer deleted successfully",
+	})
+}
+
+// GetCustomerOrders handles GET /api/customers/:id/orders
+func (h *CustomerHandler) GetCustomerOrders(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid customer ID")
+	}
+	if !canAccessCustomer(c, uint(id)) {
+		return domain.ErrCustomerNotFound
+	}
+
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return err
+	}
+
+	orders, err := h.customerUsecase.GetCustomerOrders(c.UserContext(), uint(id), page)
+	if err != nil {
+		return err
+	}
+
+	return respondPage(c, orders)
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+	"github.com/modmastei2/Go-next/backend/internal/middleware"
+	"github.com/modmastei2/Go-next/backend/pkg/logging"
+)
+
+// problem is an RFC 7807 problem details response. Code is a stable machine
+// readable error code, Errors lists the invalid fields of a validation error.
+type problem struct {
+	Type      string              `json:"type"`
+	Title     string              `json:"title"`
+	Status    int                 `json:"status"`
+	Detail    string              `json:"detail,omitempty"`
+	Instance  string              `json:"instance"`
+	Code      string              `json:"code"`
+	RequestID string              `json:"request_id,omitempty"`
+	Errors    []domain.FieldError `json:"errors,omitempty"`
+}
+
+// kindStatus maps domain error kinds to HTTP status codes
+var kindStatus = map[domain.ErrorKind]int{
+	domain.KindNotFound:          fiber.StatusNotFound,
+	domain.KindConflict:          fiber.StatusConflict,
+	domain.KindValidation:        fiber.StatusUnprocessableEntity,
+	domain.KindInsufficientStock: fiber.StatusConflict,
+	domain.KindUnauthenticated:   fiber.StatusUnauthorized,
+	domain.KindForbidden:         fiber.StatusForbidden,
+	domain.KindRateLimited:       fiber.StatusTooManyRequests,
+	domain.KindTimeout:           fiber.StatusServiceUnavailable,
+	domain.KindInternal:          fiber.StatusInternalServerError,
+}
+
+// ErrorHandler renders errors returned by handlers as application/problem+json.
+// Domain errors keep their code and message, fiber errors such as malformed
+// input or unknown routes keep their status, and anything else becomes a 500
+// whose cause is logged with the request ID but never sent to the client.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	requestID, _ := c.Locals(middleware.RequestIDKey).(string)
+	p := problem{
+		Type:      "about:blank",
+		Status:    fiber.StatusInternalServerError,
+		Instance:  c.Path(),
+		Code:      "internal_error",
+		RequestID: requestID,
+	}
+
+	var domainErr *domain.Error
+	var fiberErr *fiber.Error
+	switch {
+	case errors.As(err, &domainErr):
+		if status, ok := kindStatus[domainErr.Kind]; ok {
+			p.Status = status
+		}
+		p.Code = domainErr.Code
+		p.Detail = domainErr.Message
+		p.Errors = domainErr.Fields
+	case errors.As(err, &fiberErr):
+		p.Status = fiberErr.Code
+		p.Code = strings.ToLower(strings.ReplaceAll(utils.StatusMessage(fiberErr.Code), " ", "_"))
+		p.Detail = fiberErr.Message
+	}
+
+	if p.Status >= fiber.StatusInternalServerError {
+		logging.FromContext(c.UserContext()).Error("Request failed",
+			"method", c.Method(), "path", c.Path(), "error", err)
+		// Timeouts keep their code so clients know to retry
+		if domainErr == nil || domainErr.Kind != domain.KindTimeout {
+			p.Code = "internal_error"
+			p.Detail = "An unexpected error occurred"
+			p.Errors = nil
+		}
+	}
+
+	p.Title = utils.StatusMessage(p.Status)
+	return c.Status(p.Status).JSON(p, "application/problem+json")
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/modmastei2/Go-next/backend/pkg/health"
+)
+
+// HealthHandler handles the liveness and readiness probes
+type HealthHandler struct {
+	checks *health.Registry
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(checks *health.Registry) *HealthHandler {
+	return &HealthHandler{
+		checks: checks,
+	}
+}
+
+// Live handles GET /health/live. It checks no dependencies, a database outage
+// must not get the process restarted.
+func (h *HealthHandler) Live(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(fiber.Map{
+		"status": health.StatusUp,
+	})
+}
+
+// Ready handles GET /health/ready, it reports 503 unless every dependency is up
+func (h *HealthHandler) Ready(c *fiber.Ctx) error {
+	report := h.checks.Check(c.UserContext())
+
+	status := fiber.StatusOK
+	if report.Status != health.StatusUp {
+		status = fiber.StatusServiceUnavailable
+	}
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(status).JSON(report)
+}
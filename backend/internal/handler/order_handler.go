@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/modmastei2/Go-next/backend/internal/domain"
+	"github.com/modmastei2/Go-next/backend/internal/middleware"
 	"github.com/modmastei2/Go-next/backend/internal/usecase"
 )
 
@@ -24,17 +27,21 @@ func NewOrderHandler(orderUsecase usecase.OrderUsecase) *OrderHandler {
 func (h *OrderHandler) CreateOrder(c *fiber.Ctx) error {
 	var req domain.CreateOrderRequest
 	
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
 
-	order, err := h.orderUsecase.CreateOrder(&req)
+	// Customers may only order for themselves
+	if !canAccessCustomer(c, req.CustomerID) {
+		return domain.ErrForbidden.Withf("cannot create orders for customer %d", req.CustomerID)
+	}
+	if principal := middleware.PrincipalFrom(c); principal != nil {
+		req.CreatedBy = principal.Subject
+	}
+
+	order, err := h.orderUsecase.CreateOrder(c.UserContext(), &req)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
@@ -47,16 +54,12 @@ func (h *OrderHandler) CreateOrder(c *fiber.Ctx) error {
 func (h *OrderHandler) GetOrder(c *fiber.Ctx) error {
 	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid order ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid order ID")
 	}
 
-	order, err := h.orderUsecase.GetOrder(uint(id))
+	order, err := h.getAccessibleOrder(c, uint(id))
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Order not found",
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -65,45 +68,96 @@ func (h *OrderHandler) GetOrder(c *fiber.Ctx) error {
 }
 
 // GetOrders handles GET /api/orders
+// Query parameters: status, customer_id, customer_email, product_id, created_after,
+// created_before, min_total, max_total, currency, sort (created_at, total, status),
+// order (asc, desc), limit, offset and cursor
 func (h *OrderHandler) GetOrders(c *fiber.Ctx) error {
-	limit, _ := strconv.Atoi(c.Query("limit", "10"))
-	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+	query, err := parseOrderQuery(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	// Customers only ever see their own orders
+	if customerID, ok := ownCustomerID(c); ok {
+		query.CustomerID = customerID
+	}
 
-	orders, err := h.orderUsecase.GetOrders(limit, offset)
+	page, err := h.orderUsecase.GetOrders(c.UserContext(), query)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch orders",
-		})
+		return err
 	}
 
-	return c.JSON(fiber.Map{
-		"data": orders,
-	})
+	return respondPage(c, page)
+}
+
+// parseOrderQuery reads and validates the listing query parameters.
+// Orders are listed newest first unless another sort is requested.
+func parseOrderQuery(c *fiber.Ctx) (*domain.OrderQuery, error) {
+	var err error
+	query := &domain.OrderQuery{
+		Status:        domain.OrderStatus(c.Query("status")),
+		CustomerEmail: c.Query("customer_email"),
+		SortBy:        domain.OrderSortField(c.Query("sort", string(domain.OrderSortCreatedAt))),
+	}
+	if query.Status != "" && !query.Status.IsValid() {
+		return nil, errors.New("status must be one of pending, processing, completed, cancelled")
+	}
+	if !query.SortBy.IsValid() {
+		return nil, errors.New("sort must be one of created_at, total, status")
+	}
+
+	if query.CustomerID, err = parseIDParam(c, "customer_id"); err != nil {
+		return nil, err
+	}
+	if query.ProductID, err = parseIDParam(c, "product_id"); err != nil {
+		return nil, err
+	}
+
+	if query.CreatedAfter, err = parseTimeQuery(c, "created_after"); err != nil {
+		return nil, err
+	}
+	if query.CreatedBefore, err = parseTimeQuery(c, "created_before"); err != nil {
+		return nil, err
+	}
+
+	if c.Query("min_total") != "" || c.Query("max_total") != "" {
+		query.Currency = strings.ToUpper(c.Query("currency", domain.DefaultCurrency))
+	}
+	if query.MinTotal, err = parseMoneyParam(c, "min_total", query.Currency); err != nil {
+		return nil, err
+	}
+	if query.MaxTotal, err = parseMoneyParam(c, "max_total", query.Currency); err != nil {
+		return nil, err
+	}
+
+	if query.SortDesc, err = parseSortOrder(c, "desc"); err != nil {
+		return nil, err
+	}
+
+	if query.PageRequest, err = parsePageRequest(c); err != nil {
+		return nil, err
+	}
+
+	return query, nil
 }
 
 // UpdateOrderStatus handles PUT /api/orders/:id/status
 func (h *OrderHandler) UpdateOrderStatus(c *fiber.Ctx) error {
 	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid order ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid order ID")
 	}
 
-	var req struct {
-		Status string `json:"status"`
+	var req domain.UpdateOrderStatusRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
-
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+	if principal := middleware.PrincipalFrom(c); principal != nil {
+		req.ChangedBy = principal.Subject
 	}
 
-	if err := h.orderUsecase.UpdateOrderStatus(uint(id), req.Status); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+	if err := h.orderUsecase.UpdateOrderStatus(c.UserContext(), uint(id), &req); err != nil {
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -111,19 +165,49 @@ func (h *OrderHandler) UpdateOrderStatus(c *fiber.Ctx) error {
 	})
 }
 
+// GetOrderHistory handles GET /api/orders/:id/history
+func (h *OrderHandler) GetOrderHistory(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid order ID")
+	}
+
+	if _, err := h.getAccessibleOrder(c, uint(id)); err != nil {
+		return err
+	}
+
+	history, err := h.orderUsecase.GetOrderHistory(c.UserContext(), uint(id))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"data": history,
+	})
+}
+
+// getAccessibleOrder loads an order the caller may see. Orders of other
+// customers are reported as missing so their IDs cannot be probed.
+func (h *OrderHandler) getAccessibleOrder(c *fiber.Ctx, id uint) (*domain.Order, error) {
+	order, err := h.orderUsecase.GetOrder(c.UserContext(), id)
+	if err != nil {
+		return nil, err
+	}
+	if !canAccessCustomer(c, order.CustomerID) {
+		return nil, domain.ErrOrderNotFound
+	}
+	return order, nil
+}
+
 // DeleteOrder handles DELETE /api/orders/:id
 func (h *OrderHandler) DeleteOrder(c *fiber.Ctx) error {
 	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid order ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid order ID")
 	}
 
-	if err := h.orderUsecase.DeleteOrder(uint(id)); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to delete order",
-		})
+	if err := h.orderUsecase.DeleteOrder(c.UserContext(), uint(id)); err != nil {
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+)
+
+// paginationJSON is the pagination part of a listing response
+type paginationJSON struct {
+	Total      int64   `json:"total"`
+	Limit      int     `json:"limit"`
+	NextCursor *string `json:"next_cursor"`
+	PrevCursor *string `json:"prev_cursor"`
+}
+
+// respondPage writes a page as {"data": [...], "pagination": {...}} and
+// links the neighbouring pages in an RFC 8288 Link header
+func respondPage[T any](c *fiber.Ctx, page *domain.Page[T]) error {
+	pagination := paginationJSON{Total: page.Total, Limit: page.Limit}
+	links := []string{fmt.Sprintf(`<%s>; rel="first"`, pageURL(c, page.Limit, ""))}
+
+	if page.Next != nil {
+		next := page.Next.Encode()
+		pagination.NextCursor = &next
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL(c, page.Limit, next)))
+	}
+	if page.Prev != nil {
+		prev := page.Prev.Encode()
+		pagination.PrevCursor = &prev
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageURL(c, page.Limit, prev)))
+	}
+
+	c.Set(fiber.HeaderLink, strings.Join(links, ", "))
+	return c.JSON(fiber.Map{
+		"data":       page.Items,
+		"pagination": pagination,
+	})
+}
+
+// pageURL is the current request URL with the cursor replaced, keeping the
+// filters and sort. Offset is dropped since cursors take over from it.
+func pageURL(c *fiber.Ctx, limit int, cursor string) string {
+	query := url.Values{}
+	for key, value := range c.Queries() {
+		query.Set(key, value)
+	}
+	query.Del("offset")
+	query.Del("cursor")
+	query.Set("limit", strconv.Itoa(limit))
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	return c.BaseURL() + c.Path() + "?" + query.Encode()
+}
@@ -1,7 +1,10 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/modmastei2/Go-next/backend/internal/domain"
@@ -22,23 +25,19 @@ func NewProductHandler(productUsecase usecase.ProductUsecase) *ProductHandler {
 
 // CreateProduct handles POST /api/products
 func (h *ProductHandler) CreateProduct(c *fiber.Ctx) error {
-	var product domain.Product
-	
-	if err := c.BodyParser(&product); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+	var req domain.CreateProductRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
 
-	if err := h.productUsecase.CreateProduct(&product); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to create product",
-		})
+	product, err := h.productUsecase.CreateProduct(c.UserContext(), &req)
+	if err != nil {
+		return err
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"message": "Product created successfully",
-		"data":    product,
+		"data":    domain.NewProductResponse(product),
 	})
 }
 
@@ -46,66 +45,129 @@ func (h *ProductHandler) CreateProduct(c *fiber.Ctx) error {
 func (h *ProductHandler) GetProduct(c *fiber.Ctx) error {
 	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid product ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid product ID")
 	}
 
-	product, err := h.productUsecase.GetProduct(uint(id))
+	product, err := h.productUsecase.GetProduct(c.UserContext(), uint(id))
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Product not found",
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"data": product,
+		"data": domain.NewProductResponse(product),
 	})
 }
 
 // GetProducts handles GET /api/products
+// Query parameters: q, min_price, max_price, currency, in_stock, created_after,
+// sort (price, name, created_at), order (asc, desc), limit, offset and cursor
 func (h *ProductHandler) GetProducts(c *fiber.Ctx) error {
-	limit, _ := strconv.Atoi(c.Query("limit", "10"))
-	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+	query, err := parseProductQuery(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
 
-	products, err := h.productUsecase.GetProducts(limit, offset)
+	page, err := h.productUsecase.GetProducts(c.UserContext(), query)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch products",
-		})
+		return err
 	}
 
-	return c.JSON(fiber.Map{
-		"data": products,
-	})
+	return respondPage(c, domain.MapPage(page, domain.NewProductResponse))
+}
+
+// parseProductQuery reads and validates the listing query parameters
+func parseProductQuery(c *fiber.Ctx) (*domain.ProductQuery, error) {
+	var err error
+	query := &domain.ProductQuery{
+		Search: strings.TrimSpace(c.Query("q")),
+	}
+	if len(query.Search) > domain.MaxSearchLength {
+		return nil, fmt.Errorf("q must be at most %d characters", domain.MaxSearchLength)
+	}
+
+	if c.Query("min_price") != "" || c.Query("max_price") != "" {
+		query.Currency = strings.ToUpper(c.Query("currency", domain.DefaultCurrency))
+	}
+	if query.MinPrice, err = parseMoneyParam(c, "min_price", query.Currency); err != nil {
+		return nil, err
+	}
+	if query.MaxPrice, err = parseMoneyParam(c, "max_price", query.Currency); err != nil {
+		return nil, err
+	}
+
+	if value := c.Query("in_stock"); value != "" {
+		inStock, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.New("in_stock must be true or false")
+		}
+		query.InStock = inStock
+	}
+
+	if query.CreatedAfter, err = parseTimeQuery(c, "created_after"); err != nil {
+		return nil, err
+	}
+
+	if value := c.Query("sort"); value != "" {
+		query.SortBy = domain.ProductSortField(value)
+		if !query.SortBy.IsValid() {
+			return nil, errors.New("sort must be one of price, name, created_at")
+		}
+	}
+
+	if query.SortDesc, err = parseSortOrder(c, "asc"); err != nil {
+		return nil, err
+	}
+
+	if query.PageRequest, err = parsePageRequest(c); err != nil {
+		return nil, err
+	}
+
+	return query, nil
 }
 
 // UpdateProduct handles PUT /api/products/:id
 func (h *ProductHandler) UpdateProduct(c *fiber.Ctx) error {
 	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid product ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid product ID")
+	}
+
+	var req domain.UpdateProductRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
 
-	var product domain.Product
-	if err := c.BodyParser(&product); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+	product, err := h.productUsecase.UpdateProduct(c.UserContext(), uint(id), &req)
+	if err != nil {
+		return err
 	}
 
-	product.ID = uint(id)
-	if err := h.productUsecase.UpdateProduct(&product); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to update product",
-		})
+	return c.JSON(fiber.Map{
+		"message": "Product updated successfully",
+		"data":    domain.NewProductResponse(product),
+	})
+}
+
+// PatchProduct handles PATCH /api/products/:id
+func (h *ProductHandler) PatchProduct(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid product ID")
+	}
+
+	var req domain.PatchProductRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	product, err := h.productUsecase.PatchProduct(c.UserContext(), uint(id), &req)
+	if err != nil {
+		return err
 	}
 
 	return c.JSON(fiber.Map{
 		"message": "Product updated successfully",
-		"data":    product,
+		"data":    domain.NewProductResponse(product),
 	})
 }
 
@@ -113,15 +175,11 @@ func (h *ProductHandler) UpdateProduct(c *fiber.Ctx) error {
 func (h *ProductHandler) DeleteProduct(c *fiber.Ctx) error {
 	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid product ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid product ID")
 	}
 
-	if err := h.productUsecase.DeleteProduct(uint(id)); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to delete product",
-		})
+	if err := h.productUsecase.DeleteProduct(c.UserContext(), uint(id)); err != nil {
+		return err
 	}
 
 	return c.JSON(fiber.Map{
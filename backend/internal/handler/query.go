@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+)
+
+// parseMoneyParam parses an optional decimal amount query parameter
+func parseMoneyParam(c *fiber.Ctx, param, currency string) (*domain.Money, error) {
+	value := c.Query(param)
+	if value == "" {
+		return nil, nil
+	}
+
+	money, err := domain.ParseMoney(value, currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", param, err)
+	}
+	return &money, nil
+}
+
+// parseTimeQuery parses an optional RFC 3339 timestamp or YYYY-MM-DD date query parameter
+func parseTimeQuery(c *fiber.Ctx, param string) (*time.Time, error) {
+	value := c.Query(param)
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		if t, err = time.Parse("2006-01-02", value); err != nil {
+			return nil, fmt.Errorf("%s must be an RFC 3339 timestamp or a YYYY-MM-DD date", param)
+		}
+	}
+	return &t, nil
+}
+
+// parseIDParam parses an optional ID query parameter, zero means not set
+func parseIDParam(c *fiber.Ctx, param string) (uint, error) {
+	value := c.Query(param)
+	if value == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("%s must be a positive integer", param)
+	}
+	return uint(id), nil
+}
+
+// parseSortOrder reads the order query parameter and reports whether it is descending
+func parseSortOrder(c *fiber.Ctx, defaultOrder string) (bool, error) {
+	switch strings.ToLower(c.Query("order", defaultOrder)) {
+	case "asc":
+		return false, nil
+	case "desc":
+		return true, nil
+	}
+	return false, errors.New("order must be asc or desc")
+}
+
+// parsePageRequest reads the limit, offset and cursor query parameters.
+// The usecase applies the default and maximum page size.
+func parsePageRequest(c *fiber.Ctx) (domain.PageRequest, error) {
+	limit, _ := strconv.Atoi(c.Query("limit", "0"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+	page := domain.PageRequest{Limit: limit, Offset: offset}
+
+	if token := c.Query("cursor"); token != "" {
+		cursor, err := domain.DecodeCursor(token)
+		if err != nil {
+			return page, err
+		}
+		page.Cursor = cursor
+	}
+	return page, nil
+}
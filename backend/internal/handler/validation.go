@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+)
+
+// validate checks request bodies against their `validate` struct tags
+var validate = newValidator()
+
+// newValidator creates a validator that reports JSON field names and knows
+// the custom notblank and nonnegative rules
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	// notblank rejects strings that are empty or only whitespace
+	_ = v.RegisterValidation("notblank", func(fl validator.FieldLevel) bool {
+		return strings.TrimSpace(fl.Field().String()) != ""
+	})
+
+	// nonnegative rejects negative money amounts
+	_ = v.RegisterValidation("nonnegative", func(fl validator.FieldLevel) bool {
+		money, ok := fl.Field().Interface().(domain.Money)
+		return ok && !money.IsNegative()
+	})
+
+	return v
+}
+
+// parseBody decodes the JSON request body into body and validates it
+func parseBody(c *fiber.Ctx, body interface{}) error {
+	if err := c.BodyParser(body); err != nil {
+		// Money values report unsupported currencies as domain errors
+		var domainErr *domain.Error
+		if errors.As(err, &domainErr) {
+			return domainErr
+		}
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	return validateStruct(body)
+}
+
+// validateStruct validates a request body against its validate tags and
+// returns a validation error listing every invalid field
+func validateStruct(body interface{}) error {
+	err := validate.Struct(body)
+	if err == nil {
+		return nil
+	}
+
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return domain.Internal(err)
+	}
+
+	fields := make([]domain.FieldError, 0, len(errs))
+	for _, e := range errs {
+		// Drop the struct name, e.g. CreateOrderRequest.items[0].quantity
+		_, field, _ := strings.Cut(e.Namespace(), ".")
+		fields = append(fields, domain.FieldError{
+			Field:   field,
+			Rule:    e.Tag(),
+			Message: validationMessage(e),
+		})
+	}
+	return domain.ErrValidation.WithFields(fields)
+}
+
+// validationMessage describes a failed rule in words
+func validationMessage(e validator.FieldError) string {
+	switch e.Tag() {
+	case "required":
+		return "is required"
+	case "notblank":
+		return "must not be blank"
+	case "nonnegative":
+		return "must not be negative"
+	case "email":
+		return "must be a valid email address"
+	case "min":
+		if e.Kind() == reflect.Slice || e.Kind() == reflect.Map {
+			return fmt.Sprintf("must contain at least %s item(s)", e.Param())
+		}
+		if e.Kind() == reflect.String {
+			return fmt.Sprintf("must be at least %s characters", e.Param())
+		}
+		return "must be at least " + e.Param()
+	case "max":
+		if e.Kind() == reflect.Slice || e.Kind() == reflect.Map {
+			return fmt.Sprintf("must contain at most %s item(s)", e.Param())
+		}
+		if e.Kind() == reflect.String {
+			return fmt.Sprintf("must be at most %s characters", e.Param())
+		}
+		return "must be at most " + e.Param()
+	}
+	return fmt.Sprintf("failed the %s rule", e.Tag())
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+	"github.com/modmastei2/Go-next/backend/pkg/token"
+)
+
+// PrincipalKey is the Locals key holding the authenticated *domain.Principal
+const PrincipalKey = "principal"
+
+// APIKeyAuthenticator resolves an API key to its principal
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (*domain.Principal, error)
+}
+
+// Authenticate verifies the credentials of a request and stores its principal.
+// Users send "Authorization: Bearer <access token>", integrations send
+// "Authorization: ApiKey <key>". Requests without credentials continue
+// anonymously, RequireRole and Allow reject them on protected routes.
+func Authenticate(verifier *token.Verifier, apiKeys APIKeyAuthenticator) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		header := c.Get(fiber.HeaderAuthorization)
+		if header == "" {
+			return c.Next()
+		}
+
+		scheme, credentials, _ := strings.Cut(header, " ")
+		credentials = strings.TrimSpace(credentials)
+		if credentials == "" {
+			return unauthorized(c, domain.ErrInvalidToken)
+		}
+
+		var principal *domain.Principal
+		switch {
+		case strings.EqualFold(scheme, "Bearer"):
+			claims, err := verifier.Verify(credentials)
+			if err != nil {
+				return unauthorized(c, domain.ErrInvalidToken)
+			}
+
+			principal = &domain.Principal{
+				Subject:    claims.Subject,
+				Role:       domain.Role(claims.Role),
+				CustomerID: claims.CustomerID,
+			}
+			if !principal.Role.IsValid() || (principal.Role == domain.RoleCustomer && principal.CustomerID == 0) {
+				return unauthorized(c, domain.ErrInvalidToken)
+			}
+		case strings.EqualFold(scheme, "ApiKey"):
+			var err error
+			if principal, err = apiKeys.Authenticate(c.UserContext(), credentials); err != nil {
+				if errors.Is(err, domain.ErrInvalidAPIKey) {
+					return unauthorized(c, domain.ErrInvalidAPIKey)
+				}
+				return err
+			}
+		default:
+			return unauthorized(c, domain.ErrInvalidToken)
+		}
+
+		c.Locals(PrincipalKey, principal)
+		return c.Next()
+	}
+}
+
+// RequireRole allows only authenticated callers with one of the roles
+func RequireRole(roles ...domain.Role) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal := PrincipalFrom(c)
+		if principal == nil {
+			return unauthorized(c, domain.ErrUnauthenticated)
+		}
+		if !principal.HasRole(roles...) {
+			return domain.ErrForbidden
+		}
+		return c.Next()
+	}
+}
+
+// Allow allows users with one of the roles and API keys granted the scope
+func Allow(scope domain.Scope, roles ...domain.Role) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal := PrincipalFrom(c)
+		if principal == nil {
+			return unauthorized(c, domain.ErrUnauthenticated)
+		}
+		if !principal.HasRole(roles...) && !principal.HasScope(scope) {
+			return domain.ErrForbidden
+		}
+		return c.Next()
+	}
+}
+
+// PrincipalFrom returns the authenticated caller, or nil for anonymous requests
+func PrincipalFrom(c *fiber.Ctx) *domain.Principal {
+	principal, _ := c.Locals(PrincipalKey).(*domain.Principal)
+	return principal
+}
+
+// unauthorized asks the client to authenticate with a bearer token or API key
+func unauthorized(c *fiber.Ctx, err *domain.Error) error {
+	c.Set(fiber.HeaderWWWAuthenticate, `Bearer realm="api", ApiKey realm="api"`)
+	return err
+}
@@ -0,0 +1,172 @@
+package middleware_test
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+	"github.com/modmastei2/Go-next/backend/internal/handler"
+	"github.com/modmastei2/Go-next/backend/internal/middleware"
+	"github.com/modmastei2/Go-next/backend/pkg/token"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+// apiKeys authenticates the keys of a map
+type apiKeys map[string]*domain.Principal
+
+func (k apiKeys) Authenticate(_ context.Context, key string) (*domain.Principal, error) {
+	if principal, ok := k[key]; ok {
+		return principal, nil
+	}
+	return nil, domain.ErrInvalidAPIKey
+}
+
+// accessToken signs an access token with the test secret
+func accessToken(t *testing.T, role string, customerID uint) string {
+	t.Helper()
+
+	signer, err := token.NewSigner(token.Config{Algorithm: token.AlgorithmHS256, Secret: testSecret, AccessTTL: time.Minute})
+	if err != nil {
+		t.Fatalf("NewSigner failed: %v", err)
+	}
+	signed, err := signer.Sign(token.Claims{
+		Role:             role,
+		CustomerID:       customerID,
+		RegisteredClaims: jwt.RegisteredClaims{Subject: role + ":1"},
+	})
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	return signed
+}
+
+// newAuthApp serves routes behind Authenticate, each answering with the
+// subject of the caller
+func newAuthApp(t *testing.T) *fiber.App {
+	t.Helper()
+
+	verifier, err := token.NewVerifier(token.Config{Algorithm: token.AlgorithmHS256, Secret: testSecret})
+	if err != nil {
+		t.Fatalf("NewVerifier failed: %v", err)
+	}
+	keys := apiKeys{
+		"orders-key":  {Subject: "api-key:1", Role: domain.RoleService, Scopes: domain.Scopes{domain.ScopeOrdersRead}},
+		"metrics-key": {Subject: "api-key:2", Role: domain.RoleService, Scopes: domain.Scopes{domain.ScopeMetricsRead}},
+	}
+
+	whoami := func(c *fiber.Ctx) error {
+		if principal := middleware.PrincipalFrom(c); principal != nil {
+			return c.SendString(principal.Subject)
+		}
+		return c.SendString("anonymous")
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	app.Use(middleware.Authenticate(verifier, keys))
+	app.Get("/public", whoami)
+	app.Get("/admin", middleware.RequireRole(domain.RoleAdmin), whoami)
+	app.Get("/orders", middleware.Allow(domain.ScopeOrdersRead, domain.RoleAdmin, domain.RoleStaff), whoami)
+	return app
+}
+
+func TestAuthenticate(t *testing.T) {
+	app := newAuthApp(t)
+
+	expired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, token.Claims{
+		Role: "admin",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "admin:1",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+		},
+	}).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		header     string
+		wantStatus int
+		wantBody   string
+	}{
+		{"anonymous public", "/public", "", fiber.StatusOK, "anonymous"},
+		{"anonymous protected", "/admin", "", fiber.StatusUnauthorized, ""},
+		{"admin token", "/admin", "Bearer " + accessToken(t, "admin", 0), fiber.StatusOK, "admin:1"},
+		{"scheme is case insensitive", "/admin", "bearer " + accessToken(t, "admin", 0), fiber.StatusOK, "admin:1"},
+		{"expired token", "/public", "Bearer " + expired, fiber.StatusUnauthorized, ""},
+		{"garbage token", "/public", "Bearer not-a-token", fiber.StatusUnauthorized, ""},
+		{"empty credentials", "/public", "Bearer ", fiber.StatusUnauthorized, ""},
+		{"unknown scheme", "/public", "Basic dXNlcjpwYXNz", fiber.StatusUnauthorized, ""},
+		{"unknown role", "/public", "Bearer " + accessToken(t, "root", 0), fiber.StatusUnauthorized, ""},
+		{"service role in token", "/public", "Bearer " + accessToken(t, "service", 0), fiber.StatusUnauthorized, ""},
+		{"customer without customer_id", "/public", "Bearer " + accessToken(t, "customer", 0), fiber.StatusUnauthorized, ""},
+		{"customer with customer_id", "/public", "Bearer " + accessToken(t, "customer", 3), fiber.StatusOK, "customer:1"},
+		{"valid API key", "/public", "ApiKey orders-key", fiber.StatusOK, "api-key:1"},
+		{"unknown API key", "/public", "ApiKey guessed-key", fiber.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(fiber.MethodGet, tt.path, nil)
+		if tt.header != "" {
+			req.Header.Set(fiber.HeaderAuthorization, tt.header)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", tt.name, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+
+		if resp.StatusCode != tt.wantStatus {
+			t.Errorf("%s: status = %d, want %d (%s)", tt.name, resp.StatusCode, tt.wantStatus, body)
+			continue
+		}
+		if tt.wantStatus == fiber.StatusUnauthorized && resp.Header.Get(fiber.HeaderWWWAuthenticate) == "" {
+			t.Errorf("%s: 401 without a WWW-Authenticate header", tt.name)
+		}
+		if tt.wantBody != "" && string(body) != tt.wantBody {
+			t.Errorf("%s: body = %q, want %q", tt.name, body, tt.wantBody)
+		}
+	}
+}
+
+func TestRequireRoleAndAllow(t *testing.T) {
+	app := newAuthApp(t)
+
+	tests := []struct {
+		name       string
+		path       string
+		header     string
+		wantStatus int
+	}{
+		{"admin on admin route", "/admin", "Bearer " + accessToken(t, "admin", 0), fiber.StatusOK},
+		{"staff on admin route", "/admin", "Bearer " + accessToken(t, "staff", 0), fiber.StatusForbidden},
+		{"customer on admin route", "/admin", "Bearer " + accessToken(t, "customer", 3), fiber.StatusForbidden},
+		{"API key on admin route", "/admin", "ApiKey orders-key", fiber.StatusForbidden},
+		{"staff role allowed", "/orders", "Bearer " + accessToken(t, "staff", 0), fiber.StatusOK},
+		{"customer role not allowed", "/orders", "Bearer " + accessToken(t, "customer", 3), fiber.StatusForbidden},
+		{"API key with scope", "/orders", "ApiKey orders-key", fiber.StatusOK},
+		{"API key without scope", "/orders", "ApiKey metrics-key", fiber.StatusForbidden},
+		{"anonymous", "/orders", "", fiber.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(fiber.MethodGet, tt.path, nil)
+		if tt.header != "" {
+			req.Header.Set(fiber.HeaderAuthorization, tt.header)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", tt.name, err)
+		}
+		if resp.StatusCode != tt.wantStatus {
+			t.Errorf("%s: status = %d, want %d", tt.name, resp.StatusCode, tt.wantStatus)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/modmastei2/Go-next/backend/pkg/cors"
+)
+
+// CORS middleware handles Cross-Origin Resource Sharing with the configured policy
+func CORS(cfg cors.Config) fiber.Handler {
+	allowMethods := strings.Join(cfg.AllowMethods, ", ")
+	allowHeaders := strings.Join(cfg.AllowHeaders, ", ")
+	exposeHeaders := strings.Join(cfg.ExposeHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
+	anyOrigin := cfg.AnyOrigin()
+
+	return func(c *fiber.Ctx) error {
+		origin := c.Get(fiber.HeaderOrigin)
+		preflight := c.Method() == fiber.MethodOptions && c.Get(fiber.HeaderAccessControlRequestMethod) != ""
+
+		// Caches must not serve a response allowed for one origin to another
+		if !anyOrigin {
+			c.Vary(fiber.HeaderOrigin)
+		}
+		if preflight {
+			c.Vary(fiber.HeaderAccessControlRequestMethod, fiber.HeaderAccessControlRequestHeaders)
+		}
+
+		if origin != "" && cfg.AllowsOrigin(origin) {
+			if anyOrigin {
+				c.Set(fiber.HeaderAccessControlAllowOrigin, "*")
+			} else {
+				c.Set(fiber.HeaderAccessControlAllowOrigin, origin)
+			}
+			if cfg.AllowCredentials {
+				c.Set(fiber.HeaderAccessControlAllowCredentials, "true")
+			}
+
+			if preflight {
+				c.Set(fiber.HeaderAccessControlAllowMethods, allowMethods)
+				c.Set(fiber.HeaderAccessControlAllowHeaders, allowHeaders)
+				if cfg.MaxAge > 0 {
+					c.Set(fiber.HeaderAccessControlMaxAge, maxAge)
+				}
+			} else if exposeHeaders != "" {
+				c.Set(fiber.HeaderAccessControlExposeHeaders, exposeHeaders)
+			}
+		}
+
+		// Preflights from disallowed origins get no CORS headers, so the browser blocks the request
+		if preflight {
+			return c.SendStatus(fiber.StatusNoContent)
+		}
+
+		return c.Next()
+	}
+}
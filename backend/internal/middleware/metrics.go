@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/modmastei2/Go-next/backend/pkg/metrics"
+)
+
+// Metrics middleware counts requests and records their latency by method,
+// route pattern and status code
+func Metrics(m *metrics.HTTP) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		m.InFlight.Inc()
+		defer m.InFlight.Dec()
+
+		// Render errors first so the recorded status is final
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				return err
+			}
+		}
+
+		// Fiber reuses the method's buffer, label values are kept by the registry
+		method, route := utils.CopyString(c.Method()), c.Route().Path
+		status := strconv.Itoa(c.Response().StatusCode())
+		m.Requests.WithLabelValues(method, route, status).Inc()
+		m.Duration.WithLabelValues(method, route, status).Observe(time.Since(start).Seconds())
+		return nil
+	}
+}
@@ -1,89 +1,142 @@
 package middleware
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+	"github.com/modmastei2/Go-next/backend/pkg/logging"
+	"github.com/modmastei2/Go-next/backend/pkg/requestid"
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
-// Logger middleware logs HTTP requests
+// RequestIDKey is the Locals key holding the request ID
+const RequestIDKey = "requestID"
+
+// Logger middleware logs each HTTP request as one structured line with the
+// request ID, route, status, latency, response size and caller
 func Logger() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		start := time.Now()
-		
-		// Process request
-		err := c.Next()
-		
-		// Log request details
-		duration := time.Since(start)
-		log.Printf(
-			"[%s] %s %s - Status: %d - Duration: %v",
-			c.Method(),
-			c.Path(),
-			c.IP(),
-			c.Response().StatusCode(),
-			duration,
-		)
-		
-		return err
-	}
-}
 
-// CORS middleware handles Cross-Origin Resource Sharing
-func CORS() fiber.Handler {
-	return func(c *fiber.Ctx) error {
-		c.Set("Access-Control-Allow-Origin", "*")
-		c.Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Set("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
-		
-		// Handle preflight requests
-		if c.Method() == "OPTIONS" {
-			return c.SendStatus(fiber.StatusNoContent)
+		// Process request, rendering errors first so the logged status is final
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				return err
+			}
 		}
-		
-		return c.Next()
+
+		status := c.Response().StatusCode()
+		attrs := []slog.Attr{
+			slog.String("method", c.Method()),
+			slog.String("path", c.Path()),
+			slog.String("route", c.Route().Path),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", logging.Milliseconds(time.Since(start))),
+			slog.Int("bytes", len(c.Response().Body())),
+			slog.String("ip", c.IP()),
+		}
+		if principal := PrincipalFrom(c); principal != nil {
+			attrs = append(attrs, slog.String("user", principal.Subject), slog.String("role", string(principal.Role)))
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= fiber.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		ctx := c.UserContext()
+		logging.FromContext(ctx).LogAttrs(ctx, level, "HTTP request", attrs...)
+		return nil
 	}
 }
 
-// RequestID middleware adds a unique request ID to each request
+// RequestID middleware assigns each request an ID and a W3C trace context.
+// A valid inbound X-Request-ID is kept, otherwise a UUIDv7 is generated; an
+// inbound traceparent is continued with a new span for this server, the span
+// started by Tracing when tracing is enabled. Both are echoed in the response
+// and stored in the user context, see requestid.FromContext.
 func RequestID() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		requestID := c.Get("X-Request-ID")
-		if requestID == "" {
-			requestID = generateRequestID()
+		requestID := c.Get(requestid.Header)
+		if !requestid.IsValid(requestID) {
+			requestID = requestid.New()
 		}
+		trace := requestid.NewTrace(c.Get(requestid.TraceparentHeader), c.Get(requestid.TracestateHeader))
+
+		// With tracing enabled, use the IDs of the request's span so logs, headers and traces agree
+		span := oteltrace.SpanFromContext(c.UserContext())
+		if sc := span.SpanContext(); sc.IsValid() && !sc.IsRemote() {
+			trace.TraceID, trace.SpanID, trace.Flags = sc.TraceID().String(), sc.SpanID().String(), sc.TraceFlags().String()
+		}
+		span.SetAttributes(attribute.String("request.id", requestID))
 		
-		c.Set("X-Request-ID", requestID)
-		c.Locals("requestID", requestID)
+		c.Set(requestid.Header, requestID)
+		c.Set(requestid.TraceparentHeader, trace.String())
+		c.Locals(RequestIDKey, requestID)
+
+		// Everything logged with the request context carries the request and trace IDs
+		ctx := requestid.WithContext(c.UserContext(), requestID, trace)
+		logger := logging.FromContext(ctx).With("request_id", requestID, "trace_id", trace.TraceID, "span_id", trace.SpanID)
+		c.SetUserContext(logging.WithContext(ctx, logger))
 		
 		return c.Next()
 	}
 }
 
-// generateRequestID generates a simple request ID
-func generateRequestID() string {
-	return time.Now().Format("20060102150405") + "-" + randomString(8)
+// BaseContext middleware derives every request context from ctx, so canceling
+// ctx on shutdown cancels the requests still running
+func BaseContext(ctx context.Context) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.SetUserContext(ctx)
+		return c.Next()
+	}
 }
 
-func randomString(length int) string {
-	const charset = "abcdefghijklmnopqrstuvwxyz0123456789"
-	result := make([]byte, length)
-	for i := range result {
-		result[i] = charset[time.Now().UnixNano()%int64(len(charset))]
+// Timeout middleware gives the request context a deadline. Handlers pass
+// c.UserContext() down to the repositories, so queries still running when it
+// passes are canceled. A zero timeout disables the deadline.
+func Timeout(timeout time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if timeout <= 0 {
+			return c.Next()
+		}
+
+		ctx, cancel := context.WithTimeout(c.UserContext(), timeout)
+		defer cancel()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			// Drivers report canceled queries in their own words, which the
+			// repositories can only treat as internal errors
+			var domainErr *domain.Error
+			if !errors.As(err, &domainErr) || domainErr.Kind == domain.KindInternal {
+				return domain.Timeout(err)
+			}
+		}
+		return err
 	}
-	return string(result)
 }
 
-// Recover middleware recovers from panics
+// Recover middleware recovers from panics and reports them as an error,
+// which the error handler turns into a 500 response
 func Recover() fiber.Handler {
-	return func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) (err error) {
 		defer func() {
 			if r := recover(); r != nil {
-				log.Printf("Panic recovered: %v", r)
-				c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error": "Internal server error",
-				})
+				logging.FromContext(c.UserContext()).Error("Panic recovered",
+					"panic", r, "stack", string(debug.Stack()))
+				err = fmt.Errorf("panic: %v", r)
 			}
 		}()
 		
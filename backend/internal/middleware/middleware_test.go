@@ -0,0 +1,104 @@
+package middleware_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/modmastei2/Go-next/backend/internal/handler"
+	"github.com/modmastei2/Go-next/backend/internal/middleware"
+	"github.com/modmastei2/Go-next/backend/pkg/metrics"
+	"github.com/modmastei2/Go-next/backend/pkg/requestid"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestRequestID(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.RequestID())
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(requestid.FromContext(c.UserContext()))
+	})
+
+	tests := []struct {
+		name        string
+		requestID   string
+		traceparent string
+		keepID      bool
+		keepTrace   bool
+	}{
+		{"none", "", "", false, false},
+		{"valid", "client-42", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true, true},
+		{"too long", strings.Repeat("a", 129), "", false, false},
+		{"log injection", "id\" level=ERROR", "", false, false},
+		{"all-zero trace ID", "", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", false, false},
+		{"version ff", "", "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false, false},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		req.Header.Set(requestid.Header, tt.requestID)
+		req.Header.Set(requestid.TraceparentHeader, tt.traceparent)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", tt.name, err)
+		}
+
+		got := resp.Header.Get(requestid.Header)
+		if !requestid.IsValid(got) || (got == tt.requestID) != tt.keepID {
+			t.Errorf("%s: %s = %q, want kept %v", tt.name, requestid.Header, got, tt.keepID)
+		}
+
+		trace, ok := requestid.ParseTraceparent(resp.Header.Get(requestid.TraceparentHeader))
+		if !ok {
+			t.Errorf("%s: invalid response traceparent %q", tt.name, resp.Header.Get(requestid.TraceparentHeader))
+			continue
+		}
+		if continued := trace.TraceID == "4bf92f3577b34da6a3ce929d0e0e4736"; continued != tt.keepTrace {
+			t.Errorf("%s: response trace %s, want continued %v", tt.name, trace.TraceID, tt.keepTrace)
+		}
+		if trace.SpanID == "00f067aa0ba902b7" {
+			t.Errorf("%s: response reuses the caller's span ID", tt.name)
+		}
+	}
+}
+
+func TestRecoverInsideMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	app.Use(middleware.Metrics(metrics.NewHTTP(reg)))
+	app.Use(middleware.Recover())
+	app.Get("/panic", func(c *fiber.Ctx) error {
+		panic("boom")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/panic", nil))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", resp.StatusCode)
+	}
+
+	// The panic is counted as the 500 the client got
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	var statuses []string
+	for _, family := range families {
+		if family.GetName() != "http_requests_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "status" {
+					statuses = append(statuses, label.GetValue())
+				}
+			}
+		}
+	}
+	if len(statuses) != 1 || statuses[0] != "500" {
+		t.Errorf("counted statuses %v, want [500]", statuses)
+	}
+}
@@ -0,0 +1,119 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+	"github.com/modmastei2/Go-next/backend/internal/handler"
+	"github.com/modmastei2/Go-next/backend/internal/middleware"
+	"github.com/modmastei2/Go-next/backend/pkg/ratelimit"
+	"github.com/modmastei2/Go-next/backend/pkg/token"
+)
+
+// countingAPIKeys counts the lookups of an APIKeyAuthenticator
+type countingAPIKeys struct {
+	apiKeys
+	lookups int
+}
+
+func (k *countingAPIKeys) Authenticate(ctx context.Context, key string) (*domain.Principal, error) {
+	k.lookups++
+	return k.apiKeys.Authenticate(ctx, key)
+}
+
+// newFailureLimitedApp serves an admin route behind LimitAuthFailures and
+// Authenticate, taking the client IP from X-Forwarded-For of trusted proxies
+func newFailureLimitedApp(t *testing.T, keys *countingAPIKeys, trustedProxies ...string) *fiber.App {
+	t.Helper()
+
+	verifier, err := token.NewVerifier(token.Config{Algorithm: token.AlgorithmHS256, Secret: testSecret})
+	if err != nil {
+		t.Fatalf("NewVerifier failed: %v", err)
+	}
+
+	app := fiber.New(fiber.Config{
+		ErrorHandler:            handler.ErrorHandler,
+		ProxyHeader:             fiber.HeaderXForwardedFor,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          trustedProxies,
+		EnableIPValidation:      true,
+	})
+	app.Use(middleware.LimitAuthFailures(ratelimit.NewMemoryStore(), ratelimit.Limit{Requests: 2, Period: time.Minute}))
+	app.Use(middleware.Authenticate(verifier, keys))
+	app.Get("/admin", middleware.RequireRole(domain.RoleAdmin), func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+	return app
+}
+
+// get requests /admin with the authorization header from the client IP
+func get(t *testing.T, app *fiber.App, authorization, clientIP string) int {
+	t.Helper()
+
+	req := httptest.NewRequest(fiber.MethodGet, "/admin", nil)
+	if authorization != "" {
+		req.Header.Set(fiber.HeaderAuthorization, authorization)
+	}
+	req.Header.Set(fiber.HeaderXForwardedFor, clientIP)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	return resp.StatusCode
+}
+
+func TestLimitAuthFailures(t *testing.T) {
+	keys := &countingAPIKeys{apiKeys: apiKeys{}}
+	app := newFailureLimitedApp(t, keys)
+
+	// Requests without credentials are not guesses
+	for i := 0; i < 3; i++ {
+		if status := get(t, app, "", "203.0.113.1"); status != fiber.StatusUnauthorized {
+			t.Fatalf("anonymous request %d: status = %d, want 401", i, status)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		if status := get(t, app, "ApiKey guess", "203.0.113.1"); status != fiber.StatusUnauthorized {
+			t.Fatalf("guess %d: status = %d, want 401", i, status)
+		}
+	}
+
+	// Further guesses are rejected before the API key is looked up, valid
+	// tokens included since the IP is only known as a guesser
+	if status := get(t, app, "ApiKey guess", "203.0.113.1"); status != fiber.StatusTooManyRequests {
+		t.Errorf("guess after the limit: status = %d, want 429", status)
+	}
+	if status := get(t, app, "Bearer "+accessToken(t, "admin", 0), "203.0.113.1"); status != fiber.StatusTooManyRequests {
+		t.Errorf("token after the limit: status = %d, want 429", status)
+	}
+	if keys.lookups != 2 {
+		t.Errorf("API key looked up %d times, want 2", keys.lookups)
+	}
+
+	// The proxy is not trusted, so X-Forwarded-For is ignored and every
+	// client is seen as the proxy
+	if status := get(t, app, "Bearer "+accessToken(t, "admin", 0), "198.51.100.7"); status != fiber.StatusTooManyRequests {
+		t.Errorf("client behind an untrusted proxy: status = %d, want 429", status)
+	}
+}
+
+func TestLimitAuthFailuresBehindTrustedProxy(t *testing.T) {
+	keys := &countingAPIKeys{apiKeys: apiKeys{}}
+	// app.Test sends requests from 0.0.0.0
+	app := newFailureLimitedApp(t, keys, "0.0.0.0")
+
+	for i := 0; i < 3; i++ {
+		get(t, app, "Bearer forged", "203.0.113.1")
+	}
+	if status := get(t, app, "Bearer forged", "203.0.113.1"); status != fiber.StatusTooManyRequests {
+		t.Errorf("guessing client: status = %d, want 429", status)
+	}
+	if status := get(t, app, "Bearer "+accessToken(t, "admin", 0), "198.51.100.7"); status != fiber.StatusOK {
+		t.Errorf("other client behind the proxy: status = %d, want 200", status)
+	}
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing middleware starts a server span for each request, continuing the
+// caller's trace when the request has a traceparent header
+func Tracing() fiber.Handler {
+	tracer := otel.Tracer("github.com/modmastei2/Go-next/backend/internal/middleware")
+
+	return func(c *fiber.Ctx) error {
+		// Span attributes outlive the request, fiber reuses the request's buffers
+		method := utils.CopyString(c.Method())
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), headerCarrier{c})
+		ctx, span := tracer.Start(ctx, method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(method),
+				semconv.URLPath(utils.CopyString(c.Path())),
+			))
+		defer span.End()
+		c.SetUserContext(ctx)
+
+		// Render errors first so the recorded status is final
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				return err
+			}
+		}
+
+		route := c.Route().Path
+		status := c.Response().StatusCode()
+		span.SetName(method + " " + route)
+		span.SetAttributes(semconv.HTTPRoute(route), semconv.HTTPResponseStatusCode(status))
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, utils.StatusMessage(status))
+		}
+		return nil
+	}
+}
+
+// headerCarrier reads propagation headers such as traceparent from a request
+type headerCarrier struct {
+	c *fiber.Ctx
+}
+
+// Get returns a copy of a request header, propagators may keep the value
+func (h headerCarrier) Get(key string) string {
+	return utils.CopyString(h.c.Get(key))
+}
+
+// Set is a no-op, trace context is only read from requests
+func (h headerCarrier) Set(string, string) {}
+
+// Keys returns the names of the request headers
+func (h headerCarrier) Keys() []string {
+	headers := h.c.GetReqHeaders()
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	return keys
+}
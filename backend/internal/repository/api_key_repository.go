@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository defines the interface for API key data access
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *domain.APIKey) error
+	GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error)
+	GetAll(ctx context.Context) ([]domain.APIKey, error)
+	Revoke(ctx context.Context, id uint) error
+	TouchLastUsed(ctx context.Context, id uint, usedAt time.Time) error
+}
+
+// apiKeyRepository implements APIKeyRepository interface
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+// Create stores a new API key
+func (r *apiKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	return translateError(r.db.WithContext(ctx).Create(key).Error, nil)
+}
+
+// GetByHash retrieves an API key by the hash of the key
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	var key domain.APIKey
+	err := r.db.WithContext(ctx).Where("key_hash = ?", keyHash).First(&key).Error
+	if err != nil {
+		return nil, translateError(err, domain.ErrAPIKeyNotFound)
+	}
+	return &key, nil
+}
+
+// GetAll retrieves every API key, newest first
+func (r *apiKeyRepository) GetAll(ctx context.Context) ([]domain.APIKey, error) {
+	var keys []domain.APIKey
+	err := r.db.WithContext(ctx).Order("created_at DESC, id DESC").Find(&keys).Error
+	return keys, translateError(err, nil)
+}
+
+// Revoke revokes an API key, revoking a revoked key keeps the first revocation time
+func (r *apiKeyRepository) Revoke(ctx context.Context, id uint) error {
+	if _, err := r.getByID(ctx, id); err != nil {
+		return err
+	}
+	err := r.db.WithContext(ctx).Model(&domain.APIKey{}).Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+	return translateError(err, nil)
+}
+
+// TouchLastUsed records when an API key was last used
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id uint, usedAt time.Time) error {
+	err := r.db.WithContext(ctx).Model(&domain.APIKey{}).Where("id = ?", id).Update("last_used_at", usedAt).Error
+	return translateError(err, nil)
+}
+
+// getByID retrieves an API key by ID
+func (r *apiKeyRepository) getByID(ctx context.Context, id uint) (*domain.APIKey, error) {
+	var key domain.APIKey
+	if err := r.db.WithContext(ctx).First(&key, id).Error; err != nil {
+		return nil, translateError(err, domain.ErrAPIKeyNotFound)
+	}
+	return &key, nil
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+	"gorm.io/gorm"
+)
+
+// CredentialRepository defines the interface for customer credential data access
+type CredentialRepository interface {
+	Create(ctx context.Context, credential *domain.Credential) error
+	GetByCustomerID(ctx context.Context, customerID uint) (*domain.Credential, error)
+	UpdatePassword(ctx context.Context, customerID uint, passwordHash string) error
+}
+
+// credentialRepository implements CredentialRepository interface
+type credentialRepository struct {
+	db *gorm.DB
+}
+
+// NewCredentialRepository creates a new credential repository
+func NewCredentialRepository(db *gorm.DB) CredentialRepository {
+	return &credentialRepository{db: db}
+}
+
+// Create stores the credential of a customer
+func (r *credentialRepository) Create(ctx context.Context, credential *domain.Credential) error {
+	return translateError(r.db.WithContext(ctx).Create(credential).Error, nil)
+}
+
+// GetByCustomerID retrieves the credential of a customer
+func (r *credentialRepository) GetByCustomerID(ctx context.Context, customerID uint) (*domain.Credential, error) {
+	var credential domain.Credential
+	err := r.db.WithContext(ctx).Where("customer_id = ?", customerID).First(&credential).Error
+	if err != nil {
+		return nil, translateError(err, domain.ErrCredentialNotFound)
+	}
+	return &credential, nil
+}
+
+// UpdatePassword replaces the password hash of a customer
+func (r *credentialRepository) UpdatePassword(ctx context.Context, customerID uint, passwordHash string) error {
+	result := r.db.WithContext(ctx).Model(&domain.Credential{}).Where("customer_id = ?", customerID).
+		Updates(map[string]interface{}{"password_hash": passwordHash, "updated_at": time.Now()})
+	if result.Error != nil {
+		return translateError(result.Error, nil)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrCredentialNotFound
+	}
+	return nil
+}
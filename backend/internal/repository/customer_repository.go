@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+	"gorm.io/gorm"
+)
+
+// CustomerRepository defines the interface for customer data access
+type CustomerRepository interface {
+	Create(ctx context.Context, customer *domain.Customer) error
+	GetByID(ctx context.Context, id uint) (*domain.Customer, error)
+	GetByEmail(ctx context.Context, email string) (*domain.Customer, error)
+	GetAll(ctx context.Context, page domain.PageRequest) (*domain.Page[domain.Customer], error)
+	Update(ctx context.Context, customer *domain.Customer) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// customerRepository implements CustomerRepository interface
+type customerRepository struct {
+	db *gorm.DB
+}
+
+// NewCustomerRepository creates a new customer repository
+func NewCustomerRepository(db *gorm.DB) CustomerRepository {
+	return &customerRepository{db: db}
+}
+
+// Create creates a new customer
+func (r *customerRepository) Create(ctx context.Context, customer *domain.Customer) error {
+	return translateError(r.db.WithContext(ctx).Create(customer).Error, nil)
+}
+
+// GetByID retrieves a customer by ID
+func (r *customerRepository) GetByID(ctx context.Context, id uint) (*domain.Customer, error) {
+	var customer domain.Customer
+	err := r.db.WithContext(ctx).First(&customer, id).Error
+	if err != nil {
+		return nil, translateError(err, domain.ErrCustomerNotFound)
+	}
+	return &customer, nil
+}
+
+// GetByEmail retrieves a customer by email address
+func (r *customerRepository) GetByEmail(ctx context.Context, email string) (*domain.Customer, error) {
+	var customer domain.Customer
+	err := r.db.WithContext(ctx).Where("email = ?", email).First(&customer).Error
+	if err != nil {
+		return nil, translateError(err, domain.ErrCustomerNotFound)
+	}
+	return &customer, nil
+}
+
+// GetAll retrieves one page of customers ordered by ID
+func (r *customerRepository) GetAll(ctx context.Context, page domain.PageRequest) (*domain.Page[domain.Customer], error) {
+	db := r.db.WithContext(ctx).Model(&domain.Customer{})
+	result, err := paginate(db, page, sortKey[domain.Customer]{column: "id"}, func(c *domain.Customer) uint { return c.ID })
+	return result, translateError(err, nil)
+}
+
+// Update updates an existing customer
+func (r *customerRepository) Update(ctx context.Context, customer *domain.Customer) error {
+	return translateError(r.db.WithContext(ctx).Save(customer).Error, nil)
+}
+
+// Delete deletes a customer by ID
+func (r *customerRepository) Delete(ctx context.Context, id uint) error {
+	return deleteResult(r.db.WithContext(ctx).Delete(&domain.Customer{}, id), domain.ErrCustomerNotFound)
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+	"gorm.io/gorm"
+)
+
+// translateError maps a GORM error to a domain error. notFound is returned for
+// a missing record, unique and foreign key violations become conflicts and anything else
+// is an internal error, so raw database messages never leave the repository.
+// A canceled or expired context becomes a timeout.
+func translateError(err error, notFound *domain.Error) error {
+	var domainErr *domain.Error
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &domainErr):
+		return err
+	case errors.Is(err, gorm.ErrRecordNotFound) && notFound != nil:
+		return notFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return domain.ErrDuplicate
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return domain.ErrInUse
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return domain.Timeout(err)
+	}
+	return domain.Internal(err)
+}
+
+// deleteResult reports notFound when a delete matched no rows
+func deleteResult(result *gorm.DB, notFound *domain.Error) error {
+	if result.Error != nil {
+		return translateError(result.Error, notFound)
+	}
+	if result.RowsAffected == 0 {
+		return notFound
+	}
+	return nil
+}
@@ -1,17 +1,20 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/modmastei2/Go-next/backend/internal/domain"
 	"gorm.io/gorm"
 )
 
 // OrderRepository defines the interface for order data access
 type OrderRepository interface {
-	Create(order *domain.Order) error
-	GetByID(id uint) (*domain.Order, error)
-	GetAll(limit, offset int) ([]domain.Order, error)
-	Update(order *domain.Order) error
-	Delete(id uint) error
+	Create(ctx context.Context, order *domain.Order) error
+	GetByID(ctx context.Context, id uint) (*domain.Order, error)
+	GetAll(ctx context.Context, query *domain.OrderQuery) (*domain.Page[domain.Order], error)
+	GetByCustomerID(ctx context.Context, customerID uint, limit, offset int) ([]domain.Order, error)
+	UpdateStatus(ctx context.Context, order *domain.Order, from domain.OrderStatus) error
+	Delete(ctx context.Context, order *domain.Order) error
 }
 
 // orderRepository implements OrderRepository interface
@@ -25,34 +28,108 @@ func NewOrderRepository(db *gorm.DB) OrderRepository {
 }
 
 // Create creates a new order
-func (r *orderRepository) Create(order *domain.Order) error {
-	return r.db.Create(order).Error
+func (r *orderRepository) Create(ctx context.Context, order *domain.Order) error {
+	return translateError(r.db.WithContext(ctx).Create(order).Error, nil)
 }
 
 // GetByID retrieves an order by ID
-func (r *orderRepository) GetByID(id uint) (*domain.Order, error) {
+func (r *orderRepository) GetByID(ctx context.Context, id uint) (*domain.Order, error) {
 	var order domain.Order
-	err := r.db.Preload("Customer").Preload("Items.Product").First(&order, id).Error
+	err := r.db.WithContext(ctx).Preload("Customer").Preload("Items.Product").First(&order, id).Error
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, domain.ErrOrderNotFound)
 	}
 	return &order, nil
 }
 
-// GetAll retrieves all orders with pagination
-func (r *orderRepository) GetAll(limit, offset int) ([]domain.Order, error) {
+// orderSortKeys maps sort fields to columns, only these may reach ORDER BY
+var orderSortKeys = map[domain.OrderSortField]sortKey[domain.Order]{
+	domain.OrderSortCreatedAt: {column: "created_at", value: func(o *domain.Order) interface{} { return o.CreatedAt }},
+	domain.OrderSortTotal:     {column: "total_amount", value: func(o *domain.Order) interface{} { return o.Total.Amount }},
+	domain.OrderSortStatus:    {column: "status", value: func(o *domain.Order) interface{} { return string(o.Status) }},
+}
+
+// GetAll retrieves one page of orders matching the query
+func (r *orderRepository) GetAll(ctx context.Context, query *domain.OrderQuery) (*domain.Page[domain.Order], error) {
+	db := r.db.WithContext(ctx).Model(&domain.Order{}).Preload("Customer").Preload("Items.Product")
+
+	if query.Status != "" {
+		db = db.Where("status = ?", query.Status)
+	}
+	if query.CustomerID != 0 {
+		db = db.Where("customer_id = ?", query.CustomerID)
+	}
+	if query.CustomerEmail != "" {
+		db = db.Where("customer_id IN (?)",
+			r.db.WithContext(ctx).Model(&domain.Customer{}).Select("id").Where("email = ?", query.CustomerEmail))
+	}
+	if query.ProductID != 0 {
+		db = db.Where("id IN (?)",
+			r.db.WithContext(ctx).Model(&domain.OrderItem{}).Select("order_id").Where("product_id = ?", query.ProductID))
+	}
+	if query.CreatedAfter != nil {
+		db = db.Where("created_at > ?", *query.CreatedAfter)
+	}
+	if query.CreatedBefore != nil {
+		db = db.Where("created_at < ?", *query.CreatedBefore)
+	}
+	if query.Currency != "" {
+		db = db.Where("total_currency = ?", query.Currency)
+	}
+	if query.MinTotal != nil {
+		db = db.Where("total_amount >= ?", query.MinTotal.Amount)
+	}
+	if query.MaxTotal != nil {
+		db = db.Where("total_amount <= ?", query.MaxTotal.Amount)
+	}
+
+	key, ok := orderSortKeys[query.SortBy]
+	if !ok {
+		key = sortKey[domain.Order]{column: "id"}
+	}
+	key.desc = query.SortDesc
+
+	page, err := paginate(db, query.PageRequest, key, func(o *domain.Order) uint { return o.ID })
+	return page, translateError(err, nil)
+}
+
+// GetByCustomerID retrieves the orders of a customer, newest first
+func (r *orderRepository) GetByCustomerID(ctx context.Context, customerID uint, limit, offset int) ([]domain.Order, error) {
 	var orders []domain.Order
-	err := r.db.Preload("Customer").Preload("Items.Product").
-		Limit(limit).Offset(offset).Find(&orders).Error
-	return orders, err
+	err := r.db.WithContext(ctx).Preload("Customer").Preload("Items.Product").Where("customer_id = ?", customerID).
+		Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&orders).Error
+	return orders, translateError(err, nil)
 }
 
-// Update updates an existing order
-func (r *orderRepository) Update(order *domain.Order) error {
-	return r.db.Save(order).Error
+// UpdateStatus saves the order's new status only if it still has status from,
+// so of two concurrent changes only the first succeeds and the other gets ErrConflict
+func (r *orderRepository) UpdateStatus(ctx context.Context, order *domain.Order, from domain.OrderStatus) error {
+	result := r.db.WithContext(ctx).Model(&domain.Order{}).
+		Where("id = ? AND status = ?", order.ID, from).
+		Updates(map[string]interface{}{"status": order.Status, "updated_at": order.UpdatedAt})
+	if result.Error != nil {
+		return translateError(result.Error, nil)
+	}
+	if result.RowsAffected != 1 {
+		return domain.ErrConflict.Withf("order %d was changed by another request", order.ID)
+	}
+	return nil
 }
 
-// Delete deletes an order by ID
-func (r *orderRepository) Delete(id uint) error {
-	return r.db.Delete(&domain.Order{}, id).Error
+// Delete deletes an order and its items only if it still has the status it was
+// read with, so it cannot race a status change; call it within a transaction
+func (r *orderRepository) Delete(ctx context.Context, order *domain.Order) error {
+	db := r.db.WithContext(ctx)
+	if err := db.Where("order_id = ?", order.ID).Delete(&domain.OrderItem{}).Error; err != nil {
+		return translateError(err, nil)
+	}
+
+	result := db.Where("id = ? AND status = ?", order.ID, order.Status).Delete(&domain.Order{})
+	if result.Error != nil {
+		return translateError(result.Error, nil)
+	}
+	if result.RowsAffected != 1 {
+		return domain.ErrConflict.Withf("order %d was changed by another request", order.ID)
+	}
+	return nil
 }
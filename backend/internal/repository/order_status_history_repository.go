@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+	"gorm.io/gorm"
+)
+
+// OrderStatusHistoryRepository defines the interface for order status history data access
+type OrderStatusHistoryRepository interface {
+	Create(ctx context.Context, entry *domain.OrderStatusHistory) error
+	GetByOrderID(ctx context.Context, orderID uint) ([]domain.OrderStatusHistory, error)
+	DeleteByOrderID(ctx context.Context, orderID uint) error
+}
+
+// orderStatusHistoryRepository implements OrderStatusHistoryRepository interface
+type orderStatusHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderStatusHistoryRepository creates a new order status history repository
+func NewOrderStatusHistoryRepository(db *gorm.DB) OrderStatusHistoryRepository {
+	return &orderStatusHistoryRepository{db: db}
+}
+
+// Create records a status change
+func (r *orderStatusHistoryRepository) Create(ctx context.Context, entry *domain.OrderStatusHistory) error {
+	return translateError(r.db.WithContext(ctx).Create(entry).Error, nil)
+}
+
+// GetByOrderID retrieves the status changes of an order, oldest first
+func (r *orderStatusHistoryRepository) GetByOrderID(ctx context.Context, orderID uint) ([]domain.OrderStatusHistory, error) {
+	var history []domain.OrderStatusHistory
+	err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Order("created_at, id").Find(&history).Error
+	return history, translateError(err, nil)
+}
+
+// DeleteByOrderID removes the status changes of an order
+func (r *orderStatusHistoryRepository) DeleteByOrderID(ctx context.Context, orderID uint) error {
+	return translateError(r.db.WithContext(ctx).Where("order_id = ?", orderID).Delete(&domain.OrderStatusHistory{}).Error, nil)
+}
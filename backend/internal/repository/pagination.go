@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// sortKey is the column a listing is sorted by and how to read it from a row.
+// Rows with equal values are ordered by id in the same direction.
+type sortKey[T any] struct {
+	column string
+	desc   bool
+	value  func(row *T) interface{} // time.Time, int64 or string, nil when sorting by id
+}
+
+// name identifies the sort in cursors, e.g. "price_amount:desc"
+func (k sortKey[T]) name() string {
+	if k.desc {
+		return k.column + ":desc"
+	}
+	return k.column + ":asc"
+}
+
+// cursor points at a row of the listing
+func (k sortKey[T]) cursor(row *T, id uint, backward bool) *domain.Cursor {
+	cursor := &domain.Cursor{Sort: k.name(), ID: id, Backward: backward}
+	if k.value != nil {
+		switch value := k.value(row).(type) {
+		case time.Time:
+			cursor.Value = value.Format(time.RFC3339Nano)
+		case int64:
+			cursor.Value = strconv.FormatInt(value, 10)
+		case string:
+			cursor.Value = value
+		}
+	}
+	return cursor
+}
+
+// parse converts a cursor value back to the column's type
+func (k sortKey[T]) parse(value string) (interface{}, error) {
+	switch k.value(new(T)).(type) {
+	case time.Time:
+		return time.Parse(time.RFC3339Nano, value)
+	case int64:
+		return strconv.ParseInt(value, 10, 64)
+	}
+	return value, nil
+}
+
+// paginate loads one page of a filtered query, by cursor when the request has
+// one and by offset otherwise, together with the total count and the cursors
+// of the neighbouring pages. Cursor pages use a keyset condition on the sort
+// column and id, so they stay fast and stable on large tables.
+func paginate[T any](db *gorm.DB, page domain.PageRequest, key sortKey[T], rowID func(row *T) uint) (*domain.Page[T], error) {
+	result := &domain.Page[T]{Items: []T{}, Limit: page.Limit}
+	if err := db.Session(&gorm.Session{}).Count(&result.Total).Error; err != nil {
+		return nil, err
+	}
+
+	// A backward page scans in reverse sort order and is flipped afterwards
+	backward := page.Cursor != nil && page.Cursor.Backward
+	desc := key.desc != backward
+
+	if page.Cursor != nil {
+		if page.Cursor.Sort != key.name() {
+			return nil, domain.ErrInvalidCursor
+		}
+		op := ">"
+		if desc {
+			op = "<"
+		}
+		if key.value == nil {
+			db = db.Where("id "+op+" ?", page.Cursor.ID)
+		} else {
+			value, err := key.parse(page.Cursor.Value)
+			if err != nil {
+				return nil, domain.ErrInvalidCursor
+			}
+			db = db.Where(fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", key.column, op),
+				value, value, page.Cursor.ID)
+		}
+	} else {
+		db = db.Offset(page.Offset)
+	}
+
+	db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: key.column}, Desc: desc})
+	if key.column != "id" {
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}, Desc: desc})
+	}
+
+	// Fetch one extra row to learn whether another page follows
+	if err := db.Limit(page.Limit + 1).Find(&result.Items).Error; err != nil {
+		return nil, err
+	}
+	more := len(result.Items) > page.Limit
+	if more {
+		result.Items = result.Items[:page.Limit]
+	}
+	if backward {
+		slices.Reverse(result.Items)
+	}
+	if len(result.Items) == 0 {
+		return result, nil
+	}
+
+	first, last := &result.Items[0], &result.Items[len(result.Items)-1]
+	if backward {
+		if more {
+			result.Prev = key.cursor(first, rowID(first), true)
+		}
+		result.Next = key.cursor(last, rowID(last), false)
+	} else {
+		if more {
+			result.Next = key.cursor(last, rowID(last), false)
+		}
+		if page.Cursor != nil || page.Offset > 0 {
+			result.Prev = key.cursor(first, rowID(first), true)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+	"github.com/modmastei2/Go-next/backend/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB opens and migrates a fresh SQLite database of the test
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := database.NewDatabase(&database.Config{
+		Driver:   database.DriverSQLite,
+		Database: filepath.Join(t.TempDir(), "test.db"),
+	})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.Logger = logger.Discard
+
+	if err := database.MigrateDatabase(db); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database handle: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+// seedPaginationProducts creates products whose prices tie, so pages must
+// break ties by id
+func seedPaginationProducts(t *testing.T, db *gorm.DB) []domain.Product {
+	t.Helper()
+
+	var products []domain.Product
+	for i, price := range []string{"5", "3", "5", "1", "5", "3", "2"} {
+		product := domain.Product{
+			Name:  string(rune('a' + i)),
+			Price: domain.MustParseMoney(price, domain.DefaultCurrency),
+			Stock: 1,
+		}
+		if err := db.Create(&product).Error; err != nil {
+			t.Fatalf("failed to create product: %v", err)
+		}
+		products = append(products, product)
+	}
+	return products
+}
+
+// productIDs returns the IDs of the products in order
+func productIDs(products []domain.Product) []uint {
+	ids := make([]uint, len(products))
+	for i := range products {
+		ids[i] = products[i].ID
+	}
+	return ids
+}
+
+// roundTrip encodes and decodes a cursor as a client would send it back
+func roundTrip(t *testing.T, cursor *domain.Cursor) *domain.Cursor {
+	t.Helper()
+
+	decoded, err := domain.DecodeCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("DecodeCursor of an encoded cursor failed: %v", err)
+	}
+	return decoded
+}
+
+func TestPaginateWalksBothWays(t *testing.T) {
+	db := openTestDB(t)
+	products := seedPaginationProducts(t, db)
+	rowID := func(p *domain.Product) uint { return p.ID }
+	byPrice := func(a, b domain.Product) int {
+		if a.Price.Amount != b.Price.Amount {
+			return int(a.Price.Amount - b.Price.Amount)
+		}
+		return int(a.ID) - int(b.ID)
+	}
+
+	tests := []struct {
+		name string
+		key  sortKey[domain.Product]
+		cmp  func(a, b domain.Product) int
+	}{
+		{"id", sortKey[domain.Product]{column: "id"}, func(a, b domain.Product) int { return int(a.ID) - int(b.ID) }},
+		{"id desc", sortKey[domain.Product]{column: "id", desc: true}, func(a, b domain.Product) int { return int(b.ID) - int(a.ID) }},
+		{"price", productSortKeys[domain.ProductSortPrice], byPrice},
+		{"price desc", sortKey[domain.Product]{column: "price_amount", desc: true, value: productSortKeys[domain.ProductSortPrice].value},
+			func(a, b domain.Product) int { return byPrice(b, a) }},
+		{"name", productSortKeys[domain.ProductSortName], func(a, b domain.Product) int { return int(a.Name[0]) - int(b.Name[0]) }},
+		{"created_at", productSortKeys[domain.ProductSortCreatedAt], func(a, b domain.Product) int {
+			if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+				return c
+			}
+			return int(a.ID) - int(b.ID)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := slices.Clone(products)
+			slices.SortFunc(want, tt.cmp)
+			wantIDs := productIDs(want)
+
+			// Forward through every page by the next cursors
+			var pages [][]uint
+			var cursor *domain.Cursor
+			for {
+				page, err := paginate(db.Model(&domain.Product{}), domain.PageRequest{Limit: 2, Cursor: cursor}, tt.key, rowID)
+				if err != nil {
+					t.Fatalf("paginate failed: %v", err)
+				}
+				if page.Total != int64(len(products)) {
+					t.Errorf("total = %d, want %d", page.Total, len(products))
+				}
+				if (page.Prev == nil) != (len(pages) == 0) {
+					t.Errorf("page %d: prev cursor %+v", len(pages), page.Prev)
+				}
+				pages = append(pages, productIDs(page.Items))
+				if page.Next == nil {
+					break
+				}
+				cursor = roundTrip(t, page.Next)
+			}
+			if got := slices.Concat(pages...); !slices.Equal(got, wantIDs) {
+				t.Fatalf("forward pages = %v, want %v", pages, wantIDs)
+			}
+
+			// Backward from the last page by the prev cursors
+			page, err := paginate(db.Model(&domain.Product{}), domain.PageRequest{Limit: 2, Cursor: cursor}, tt.key, rowID)
+			if err != nil {
+				t.Fatalf("paginate failed: %v", err)
+			}
+			for i := len(pages) - 2; i >= 0; i-- {
+				page, err = paginate(db.Model(&domain.Product{}), domain.PageRequest{Limit: 2, Cursor: roundTrip(t, page.Prev)}, tt.key, rowID)
+				if err != nil {
+					t.Fatalf("paginate failed: %v", err)
+				}
+				if got := productIDs(page.Items); !slices.Equal(got, pages[i]) {
+					t.Errorf("backward page %d = %v, want %v", i, got, pages[i])
+				}
+				if page.Next == nil {
+					t.Errorf("backward page %d has no next cursor", i)
+				}
+			}
+			if page.Prev != nil {
+				t.Errorf("first page reached backward has prev cursor %+v", page.Prev)
+			}
+		})
+	}
+}
+
+func TestPaginateByOffset(t *testing.T) {
+	db := openTestDB(t)
+	products := seedPaginationProducts(t, db)
+	key := sortKey[domain.Product]{column: "id"}
+
+	page, err := paginate(db.Model(&domain.Product{}), domain.PageRequest{Limit: 3, Offset: 3}, key, func(p *domain.Product) uint { return p.ID })
+	if err != nil {
+		t.Fatalf("paginate failed: %v", err)
+	}
+	if got, want := productIDs(page.Items), productIDs(products[3:6]); !slices.Equal(got, want) {
+		t.Errorf("offset page = %v, want %v", got, want)
+	}
+	if page.Prev == nil || page.Next == nil {
+		t.Errorf("middle offset page has prev %+v and next %+v, want both", page.Prev, page.Next)
+	}
+
+	// A prev cursor from an offset page leads to the rows before it
+	page, err = paginate(db.Model(&domain.Product{}), domain.PageRequest{Limit: 3, Cursor: roundTrip(t, page.Prev)}, key, func(p *domain.Product) uint { return p.ID })
+	if err != nil {
+		t.Fatalf("paginate failed: %v", err)
+	}
+	if got, want := productIDs(page.Items), productIDs(products[:3]); !slices.Equal(got, want) {
+		t.Errorf("page before the offset = %v, want %v", got, want)
+	}
+}
+
+func TestPaginateRejectsInvalidCursors(t *testing.T) {
+	db := openTestDB(t)
+	seedPaginationProducts(t, db)
+	byPrice := productSortKeys[domain.ProductSortPrice]
+	rowID := func(p *domain.Product) uint { return p.ID }
+
+	cursors := map[string]*domain.Cursor{
+		"other sort":       {Sort: "name:asc", Value: "a", ID: 1},
+		"other direction":  {Sort: "price_amount:desc", Value: "500", ID: 1},
+		"unparsable value": {Sort: "price_amount:asc", Value: "five", ID: 1},
+	}
+	for name, cursor := range cursors {
+		_, err := paginate(db.Model(&domain.Product{}), domain.PageRequest{Limit: 2, Cursor: cursor}, byPrice, rowID)
+		if !errors.Is(err, domain.ErrInvalidCursor) {
+			t.Errorf("%s: paginate = %v, want %v", name, err, domain.ErrInvalidCursor)
+		}
+	}
+
+	for _, token := range []string{"", "not base64!", "bm90IGpzb24", domain.Cursor{Sort: "id:asc"}.Encode(), domain.Cursor{ID: 3}.Encode()} {
+		if _, err := domain.DecodeCursor(token); !errors.Is(err, domain.ErrInvalidCursor) {
+			t.Errorf("DecodeCursor(%q) = %v, want %v", token, err, domain.ErrInvalidCursor)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+	"gorm.io/gorm"
+)
+
+// PasswordResetRepository defines the interface for password reset token data access
+type PasswordResetRepository interface {
+	Create(ctx context.Context, token *domain.PasswordResetToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error)
+	MarkUsed(ctx context.Context, id uint) error
+}
+
+// passwordResetRepository implements PasswordResetRepository interface
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+// NewPasswordResetRepository creates a new password reset repository
+func NewPasswordResetRepository(db *gorm.DB) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+// Create stores an issued password reset token
+func (r *passwordResetRepository) Create(ctx context.Context, token *domain.PasswordResetToken) error {
+	return translateError(r.db.WithContext(ctx).Create(token).Error, nil)
+}
+
+// GetByHash retrieves a password reset token by the hash of its value
+func (r *passwordResetRepository) GetByHash(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+	var token domain.PasswordResetToken
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		return nil, translateError(err, domain.ErrResetTokenNotFound)
+	}
+	return &token, nil
+}
+
+// MarkUsed marks an unused token as used. It returns ErrResetTokenNotFound
+// when the token was already used, so a token works only once.
+func (r *passwordResetRepository) MarkUsed(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Model(&domain.PasswordResetToken{}).Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return translateError(result.Error, nil)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrResetTokenNotFound
+	}
+	return nil
+}
@@ -1,17 +1,23 @@
 package repository
 
 import (
+	"context"
+	"strings"
+	"time"
+
 	"github.com/modmastei2/Go-next/backend/internal/domain"
 	"gorm.io/gorm"
 )
 
 // ProductRepository defines the interface for product data access
 type ProductRepository interface {
-	Create(product *domain.Product) error
-	GetByID(id uint) (*domain.Product, error)
-	GetAll(limit, offset int) ([]domain.Product, error)
-	Update(product *domain.Product) error
-	Delete(id uint) error
+	Create(ctx context.Context, product *domain.Product) error
+	GetByID(ctx context.Context, id uint) (*domain.Product, error)
+	GetAll(ctx context.Context, query *domain.ProductQuery) (*domain.Page[domain.Product], error)
+	Patch(ctx context.Context, id uint, patch *domain.PatchProductRequest) error
+	Delete(ctx context.Context, id uint) error
+	ReserveStock(ctx context.Context, id uint, quantity int) error
+	ReleaseStock(ctx context.Context, id uint, quantity int) error
 }
 
 // productRepository implements ProductRepository interface
@@ -25,33 +31,141 @@ func NewProductRepository(db *gorm.DB) ProductRepository {
 }
 
 // Create creates a new product
-func (r *productRepository) Create(product *domain.Product) error {
-	return r.db.Create(product).Error
+func (r *productRepository) Create(ctx context.Context, product *domain.Product) error {
+	return translateError(r.db.WithContext(ctx).Create(product).Error, nil)
 }
 
 // GetByID retrieves a product by ID
-func (r *productRepository) GetByID(id uint) (*domain.Product, error) {
+func (r *productRepository) GetByID(ctx context.Context, id uint) (*domain.Product, error) {
 	var product domain.Product
-	err := r.db.First(&product, id).Error
+	err := r.db.WithContext(ctx).First(&product, id).Error
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, domain.ErrProductNotFound)
 	}
 	return &product, nil
 }
 
-// GetAll retrieves all products with pagination
-func (r *productRepository) GetAll(limit, offset int) ([]domain.Product, error) {
-	var products []domain.Product
-	err := r.db.Limit(limit).Offset(offset).Find(&products).Error
-	return products, err
+// productSortKeys maps sort fields to columns, only these may reach ORDER BY
+var productSortKeys = map[domain.ProductSortField]sortKey[domain.Product]{
+	domain.ProductSortCreatedAt: {column: "created_at", value: func(p *domain.Product) interface{} { return p.CreatedAt }},
+	domain.ProductSortName:      {column: "name", value: func(p *domain.Product) interface{} { return p.Name }},
+	domain.ProductSortPrice:     {column: "price_amount", value: func(p *domain.Product) interface{} { return p.Price.Amount }},
+}
+
+// GetAll retrieves one page of products matching the query
+func (r *productRepository) GetAll(ctx context.Context, query *domain.ProductQuery) (*domain.Page[domain.Product], error) {
+	db := r.db.WithContext(ctx).Model(&domain.Product{})
+
+	for _, term := range strings.Fields(strings.ToLower(query.Search)) {
+		pattern := "%" + escapeLike(term) + "%"
+		db = db.Where("(LOWER(name) LIKE ? ESCAPE '!' OR LOWER(description) LIKE ? ESCAPE '!')", pattern, pattern)
+	}
+	if query.Currency != "" {
+		db = db.Where("price_currency = ?", query.Currency)
+	}
+	if query.MinPrice != nil {
+		db = db.Where("price_amount >= ?", query.MinPrice.Amount)
+	}
+	if query.MaxPrice != nil {
+		db = db.Where("price_amount <= ?", query.MaxPrice.Amount)
+	}
+	if query.InStock {
+		db = db.Where("stock > 0")
+	}
+	if query.CreatedAfter != nil {
+		db = db.Where("created_at > ?", *query.CreatedAfter)
+	}
+
+	key, ok := productSortKeys[query.SortBy]
+	if !ok {
+		key = sortKey[domain.Product]{column: "id"}
+	}
+	key.desc = query.SortDesc
+
+	page, err := paginate(db, query.PageRequest, key, func(p *domain.Product) uint { return p.ID })
+	return page, translateError(err, nil)
 }
 
-// Update updates an existing product
-func (r *productRepository) Update(product *domain.Product) error {
-	return r.db.Save(product).Error
+// escapeLike escapes LIKE wildcards in user input using '!' as the escape character,
+// which unlike a backslash means the same thing on every supported database
+func escapeLike(value string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_", "[", "![").Replace(value)
+}
+
+// Patch updates only the fields set in the patch with a single UPDATE, so
+// concurrent stock reservations are not overwritten by stale values
+func (r *productRepository) Patch(ctx context.Context, id uint, patch *domain.PatchProductRequest) error {
+	columns := map[string]interface{}{"updated_at": time.Now()}
+	if patch.Name != nil {
+		columns["name"] = *patch.Name
+	}
+	if patch.Description != nil {
+		columns["description"] = *patch.Description
+	}
+	if patch.Price != nil {
+		columns["price_amount"] = patch.Price.Amount
+		columns["price_currency"] = patch.Price.Currency
+	}
+	if patch.Stock != nil {
+		columns["stock"] = *patch.Stock
+	}
+
+	result := r.db.WithContext(ctx).Model(&domain.Product{}).Where("id = ?", id).UpdateColumns(columns)
+	if result.Error != nil {
+		return translateError(result.Error, nil)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrProductNotFound
+	}
+	return nil
 }
 
 // Delete deletes a product by ID
-func (r *productRepository) Delete(id uint) error {
-	return r.db.Delete(&domain.Product{}, id).Error
+func (r *productRepository) Delete(ctx context.Context, id uint) error {
+	return deleteResult(r.db.WithContext(ctx).Delete(&domain.Product{}, id), domain.ErrProductNotFound)
+}
+
+// ReserveStock atomically decrements stock when enough is available.
+// The check and the decrement are a single conditional UPDATE, so concurrent
+// reservations can never drive stock below zero.
+func (r *productRepository) ReserveStock(ctx context.Context, id uint, quantity int) error {
+	result := r.db.WithContext(ctx).Model(&domain.Product{}).
+		Where("id = ? AND stock >= ?", id, quantity).
+		UpdateColumns(map[string]interface{}{
+			"stock":      gorm.Expr("stock - ?", quantity),
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return translateError(result.Error, nil)
+	}
+
+	if result.RowsAffected == 0 {
+		// Nothing matched: either the product is gone or stock is too low
+		var count int64
+		if err := r.db.WithContext(ctx).Model(&domain.Product{}).Where("id = ?", id).Count(&count).Error; err != nil {
+			return translateError(err, nil)
+		}
+		if count == 0 {
+			return domain.ErrProductNotFound
+		}
+		return domain.ErrInsufficientStock
+	}
+	return nil
+}
+
+// ReleaseStock atomically returns previously reserved stock
+func (r *productRepository) ReleaseStock(ctx context.Context, id uint, quantity int) error {
+	result := r.db.WithContext(ctx).Model(&domain.Product{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"stock":      gorm.Expr("stock + ?", quantity),
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return translateError(result.Error, nil)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrProductNotFound
+	}
+	return nil
 }
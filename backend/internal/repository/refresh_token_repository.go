@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+	"gorm.io/gorm"
+)
+
+// RefreshTokenRepository defines the interface for refresh token data access
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *domain.RefreshToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+	Revoke(ctx context.Context, id uint) error
+	RevokeAllForCustomer(ctx context.Context, customerID uint) error
+}
+
+// refreshTokenRepository implements RefreshTokenRepository interface
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewRefreshTokenRepository creates a new refresh token repository
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+// Create stores an issued refresh token
+func (r *refreshTokenRepository) Create(ctx context.Context, token *domain.RefreshToken) error {
+	return translateError(r.db.WithContext(ctx).Create(token).Error, nil)
+}
+
+// GetByHash retrieves a refresh token by the hash of its value
+func (r *refreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		return nil, translateError(err, domain.ErrRefreshTokenNotFound)
+	}
+	return &token, nil
+}
+
+// Revoke revokes an active refresh token. It returns ErrRefreshTokenNotFound
+// when the token was already revoked, so concurrent refreshes of the same
+// token cannot both succeed.
+func (r *refreshTokenRepository) Revoke(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Model(&domain.RefreshToken{}).Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return translateError(result.Error, nil)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrRefreshTokenNotFound
+	}
+	return nil
+}
+
+// RevokeAllForCustomer revokes every active refresh token of a customer
+func (r *refreshTokenRepository) RevokeAllForCustomer(ctx context.Context, customerID uint) error {
+	err := r.db.WithContext(ctx).Model(&domain.RefreshToken{}).Where("customer_id = ? AND revoked_at IS NULL", customerID).
+		Update("revoked_at", time.Now()).Error
+	return translateError(err, nil)
+}
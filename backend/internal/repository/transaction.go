@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Repositories groups the repositories bound to the same database handle
+type Repositories struct {
+	Customers     CustomerRepository
+	Orders        OrderRepository
+	Products      ProductRepository
+	StatusHistory OrderStatusHistoryRepository
+	Credentials   CredentialRepository
+	RefreshTokens RefreshTokenRepository
+	ResetTokens   PasswordResetRepository
+	APIKeys       APIKeyRepository
+}
+
+// NewRepositories creates all repositories on top of the given database handle
+func NewRepositories(db *gorm.DB) *Repositories {
+	return &Repositories{
+		Customers:     NewCustomerRepository(db),
+		Orders:        NewOrderRepository(db),
+		Products:      NewProductRepository(db),
+		StatusHistory: NewOrderStatusHistoryRepository(db),
+		Credentials:   NewCredentialRepository(db),
+		RefreshTokens: NewRefreshTokenRepository(db),
+		ResetTokens:   NewPasswordResetRepository(db),
+		APIKeys:       NewAPIKeyRepository(db),
+	}
+}
+
+// TxManager defines the interface for running work in a database transaction
+type TxManager interface {
+	// WithinTransaction runs fn with repositories bound to a single transaction.
+	// The transaction is committed when fn returns nil and rolled back otherwise.
+	WithinTransaction(ctx context.Context, fn func(repos *Repositories) error) error
+}
+
+// txManager implements TxManager interface
+type txManager struct {
+	db *gorm.DB
+}
+
+// NewTxManager creates a new transaction manager
+func NewTxManager(db *gorm.DB) TxManager {
+	return &txManager{db: db}
+}
+
+// WithinTransaction runs fn inside a transaction
+func (m *txManager) WithinTransaction(ctx context.Context, fn func(repos *Repositories) error) error {
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(NewRepositories(tx))
+	})
+	return translateError(err, nil)
+}
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+	"github.com/modmastei2/Go-next/backend/internal/repository"
+)
+
+// apiKeyPrefix starts every API key so leaked keys are easy to recognise and scan for
+const apiKeyPrefix = "sk_"
+
+// lastUsedInterval limits how often the last use of a key is written
+const lastUsedInterval = time.Minute
+
+// APIKeyUsecase defines the interface for API key business logic
+type APIKeyUsecase interface {
+	CreateAPIKey(ctx context.Context, req *domain.CreateAPIKeyRequest) (*domain.CreatedAPIKey, error)
+	GetAPIKeys(ctx context.Context) ([]domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id uint) error
+	Authenticate(ctx context.Context, key string) (*domain.Principal, error)
+}
+
+// apiKeyUsecase implements APIKeyUsecase interface
+type apiKeyUsecase struct {
+	apiKeyRepo repository.APIKeyRepository
+}
+
+// NewAPIKeyUsecase creates a new API key usecase
+func NewAPIKeyUsecase(apiKeyRepo repository.APIKeyRepository) APIKeyUsecase {
+	return &apiKeyUsecase{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// CreateAPIKey creates an API key, the returned key is not stored and cannot be shown again
+func (u *apiKeyUsecase) CreateAPIKey(ctx context.Context, req *domain.CreateAPIKeyRequest) (*domain.CreatedAPIKey, error) {
+	scopes := domain.Scopes{}
+	for _, scope := range req.Scopes {
+		if !scope.IsValid() {
+			return nil, domain.ErrInvalidScope.Withf("invalid scope: %s", scope)
+		}
+		if !scopes.Has(scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, domain.ErrValidation.Withf("expires_at must be in the future")
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, domain.Internal(fmt.Errorf("failed to generate API key: %w", err))
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(buf)
+
+	apiKey := domain.APIKey{
+		Name:      strings.TrimSpace(req.Name),
+		Prefix:    key[:len(apiKeyPrefix)+8],
+		KeyHash:   hashToken(key),
+		Scopes:    scopes,
+		ExpiresAt: req.ExpiresAt,
+		CreatedBy: req.CreatedBy,
+		CreatedAt: time.Now(),
+	}
+	if err := u.apiKeyRepo.Create(ctx, &apiKey); err != nil {
+		return nil, err
+	}
+	return &domain.CreatedAPIKey{APIKey: apiKey, Key: key}, nil
+}
+
+// GetAPIKeys retrieves every API key including revoked and expired ones
+func (u *apiKeyUsecase) GetAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	return u.apiKeyRepo.GetAll(ctx)
+}
+
+// RevokeAPIKey revokes an API key, it stops working immediately
+func (u *apiKeyUsecase) RevokeAPIKey(ctx context.Context, id uint) error {
+	return u.apiKeyRepo.Revoke(ctx, id)
+}
+
+// Authenticate returns the principal of an active API key and records its use
+func (u *apiKeyUsecase) Authenticate(ctx context.Context, key string) (*domain.Principal, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	apiKey, err := u.apiKeyRepo.GetByHash(ctx, hashToken(key))
+	if errors.Is(err, domain.ErrAPIKeyNotFound) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !apiKey.IsActive(now) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	// Busy integrations would otherwise write on every request
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= lastUsedInterval {
+		if err := u.apiKeyRepo.TouchLastUsed(ctx, apiKey.ID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	return &domain.Principal{
+		Subject: fmt.Sprintf("api_key:%d", apiKey.ID),
+		Role:    domain.RoleService,
+		Scopes:  apiKey.Scopes,
+	}, nil
+}
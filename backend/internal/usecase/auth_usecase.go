@@ -0,0 +1,341 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+	"github.com/modmastei2/Go-next/backend/internal/repository"
+	"github.com/modmastei2/Go-next/backend/pkg/notify"
+	"github.com/modmastei2/Go-next/backend/pkg/token"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// AuthUsecase defines the interface for customer account business logic
+type AuthUsecase interface {
+	Register(ctx context.Context, req *domain.RegisterRequest) (*domain.Customer, error)
+	Login(ctx context.Context, req *domain.LoginRequest) (*domain.TokenResponse, error)
+	Refresh(ctx context.Context, refreshToken string) (*domain.TokenResponse, error)
+	Logout(ctx context.Context, refreshToken string) error
+	RequestPasswordReset(ctx context.Context, req *domain.PasswordResetRequest) error
+	ResetPassword(ctx context.Context, req *domain.ConfirmPasswordResetRequest) error
+}
+
+// authUsecase implements AuthUsecase interface
+type authUsecase struct {
+	customerRepo     repository.CustomerRepository
+	credentialRepo   repository.CredentialRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	resetTokenRepo   repository.PasswordResetRepository
+	txManager        repository.TxManager
+	signer           *token.Signer
+	notifier         notify.Notifier
+	refreshTTL       time.Duration
+	resetTTL         time.Duration
+}
+
+// NewAuthUsecase creates a new auth usecase. Refresh tokens live for
+// refreshTTL and password reset tokens for resetTTL. signer is nil when the
+// server cannot issue tokens, Login and Refresh must not be called then.
+func NewAuthUsecase(
+	customerRepo repository.CustomerRepository,
+	credentialRepo repository.CredentialRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	resetTokenRepo repository.PasswordResetRepository,
+	txManager repository.TxManager,
+	signer *token.Signer,
+	notifier notify.Notifier,
+	refreshTTL, resetTTL time.Duration,
+) AuthUsecase {
+	return &authUsecase{
+		customerRepo:     customerRepo,
+		credentialRepo:   credentialRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		resetTokenRepo:   resetTokenRepo,
+		txManager:        txManager,
+		signer:           signer,
+		notifier:         notifier,
+		refreshTTL:       refreshTTL,
+		resetTTL:         resetTTL,
+	}
+}
+
+// dummyPasswordHash is compared against when an account does not exist, so
+// logins take as long for unknown emails as for wrong passwords
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("no account"), bcrypt.DefaultCost)
+
+// Register creates a customer together with their password
+func (u *authUsecase) Register(ctx context.Context, req *domain.RegisterRequest) (*domain.Customer, error) {
+	name, email, err := normalizeCustomer(req.Name, req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordHash, err := hashPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	customer := &domain.Customer{Name: name, Email: email, CreatedAt: now, UpdatedAt: now}
+	err = u.txManager.WithinTransaction(ctx, func(repos *repository.Repositories) error {
+		if _, err := repos.Customers.GetByEmail(ctx, email); err == nil {
+			return domain.ErrEmailTaken
+		} else if !errors.Is(err, domain.ErrCustomerNotFound) {
+			return err
+		}
+
+		if err := repos.Customers.Create(ctx, customer); err != nil {
+			return emailTaken(err)
+		}
+		return repos.Credentials.Create(ctx, &domain.Credential{
+			CustomerID:   customer.ID,
+			PasswordHash: passwordHash,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return customer, nil
+}
+
+// Login checks an email and password and issues access and refresh tokens
+func (u *authUsecase) Login(ctx context.Context, req *domain.LoginRequest) (*domain.TokenResponse, error) {
+	customer, err := u.customerRepo.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(req.Email)))
+	if err != nil && !errors.Is(err, domain.ErrCustomerNotFound) {
+		return nil, err
+	}
+
+	passwordHash := dummyPasswordHash
+	if customer != nil {
+		credential, err := u.credentialRepo.GetByCustomerID(ctx, customer.ID)
+		switch {
+		case err == nil:
+			passwordHash = []byte(credential.PasswordHash)
+		case !errors.Is(err, domain.ErrCredentialNotFound):
+			return nil, err
+		default:
+			customer = nil
+		}
+	}
+
+	if bcrypt.CompareHashAndPassword(passwordHash, []byte(req.Password)) != nil || customer == nil {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	return u.issueTokens(ctx, u.refreshTokenRepo, customer.ID)
+}
+
+// Refresh exchanges a refresh token for new tokens. The old refresh token is
+// revoked; presenting a revoked token again means it was stolen or replayed,
+// so every session of the customer is revoked.
+func (u *authUsecase) Refresh(ctx context.Context, refreshToken string) (*domain.TokenResponse, error) {
+	stored, err := u.refreshTokenRepo.GetByHash(ctx, hashToken(refreshToken))
+	if errors.Is(err, domain.ErrRefreshTokenNotFound) {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if stored.RevokedAt != nil {
+		if err := u.refreshTokenRepo.RevokeAllForCustomer(ctx, stored.CustomerID); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrInvalidRefreshToken
+	}
+	if !stored.IsActive(time.Now()) {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	var tokens *domain.TokenResponse
+	err = u.txManager.WithinTransaction(ctx, func(repos *repository.Repositories) error {
+		if err := repos.RefreshTokens.Revoke(ctx, stored.ID); err != nil {
+			if errors.Is(err, domain.ErrRefreshTokenNotFound) {
+				return domain.ErrInvalidRefreshToken
+			}
+			return err
+		}
+
+		tokens, err = u.issueTokens(ctx, repos.RefreshTokens, stored.CustomerID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// Logout revokes a refresh token. Unknown and already revoked tokens are ignored.
+func (u *authUsecase) Logout(ctx context.Context, refreshToken string) error {
+	stored, err := u.refreshTokenRepo.GetByHash(ctx, hashToken(refreshToken))
+	if errors.Is(err, domain.ErrRefreshTokenNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := u.refreshTokenRepo.Revoke(ctx, stored.ID); err != nil && !errors.Is(err, domain.ErrRefreshTokenNotFound) {
+		return err
+	}
+	return nil
+}
+
+// RequestPasswordReset sends a one-time reset token to the customer. Unknown
+// emails are silently ignored so the endpoint cannot be used to find accounts.
+func (u *authUsecase) RequestPasswordReset(ctx context.Context, req *domain.PasswordResetRequest) error {
+	customer, err := u.customerRepo.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(req.Email)))
+	if errors.Is(err, domain.ErrCustomerNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	resetToken, tokenHash, err := newOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(u.resetTTL)
+	err = u.resetTokenRepo.Create(ctx, &domain.PasswordResetToken{
+		CustomerID: customer.ID,
+		TokenHash:  tokenHash,
+		ExpiresAt:  expiresAt,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	err = u.notifier.Notify(ctx, notify.Message{
+		To:      customer.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Use this token to set a new password: %s\nIt expires at %s.",
+			resetToken, expiresAt.Format(time.RFC1123)),
+	})
+	if err != nil {
+		return domain.Internal(fmt.Errorf("failed to send password reset token: %w", err))
+	}
+	return nil
+}
+
+// ResetPassword sets a new password with a reset token and signs the customer
+// out everywhere. Customers created by staff have no password yet and get one.
+func (u *authUsecase) ResetPassword(ctx context.Context, req *domain.ConfirmPasswordResetRequest) error {
+	stored, err := u.resetTokenRepo.GetByHash(ctx, hashToken(req.Token))
+	if errors.Is(err, domain.ErrResetTokenNotFound) {
+		return domain.ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+	if !stored.IsActive(time.Now()) {
+		return domain.ErrInvalidResetToken
+	}
+
+	passwordHash, err := hashPassword(req.Password)
+	if err != nil {
+		return err
+	}
+
+	return u.txManager.WithinTransaction(ctx, func(repos *repository.Repositories) error {
+		if err := repos.ResetTokens.MarkUsed(ctx, stored.ID); err != nil {
+			if errors.Is(err, domain.ErrResetTokenNotFound) {
+				return domain.ErrInvalidResetToken
+			}
+			return err
+		}
+
+		err := repos.Credentials.UpdatePassword(ctx, stored.CustomerID, passwordHash)
+		if errors.Is(err, domain.ErrCredentialNotFound) {
+			now := time.Now()
+			err = repos.Credentials.Create(ctx, &domain.Credential{
+				CustomerID:   stored.CustomerID,
+				PasswordHash: passwordHash,
+				CreatedAt:    now,
+				UpdatedAt:    now,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		return repos.RefreshTokens.RevokeAllForCustomer(ctx, stored.CustomerID)
+	})
+}
+
+// issueTokens signs an access token for a customer and stores a new refresh token
+func (u *authUsecase) issueTokens(ctx context.Context, refreshTokens repository.RefreshTokenRepository, customerID uint) (*domain.TokenResponse, error) {
+	accessToken, err := u.signer.Sign(token.Claims{
+		Role:       string(domain.RoleCustomer),
+		CustomerID: customerID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject: fmt.Sprintf("customer:%d", customerID),
+		},
+	})
+	if err != nil {
+		return nil, domain.Internal(fmt.Errorf("failed to sign access token: %w", err))
+	}
+
+	refreshToken, tokenHash, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	err = refreshTokens.Create(ctx, &domain.RefreshToken{
+		CustomerID: customerID,
+		TokenHash:  tokenHash,
+		ExpiresAt:  time.Now().Add(u.refreshTTL),
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(u.signer.TTL().Seconds()),
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// hashPassword hashes a password with bcrypt
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+		return "", domain.ErrValidation.Withf("password must be at most 72 bytes")
+	}
+	if err != nil {
+		return "", domain.Internal(err)
+	}
+	return string(hash), nil
+}
+
+// newOpaqueToken returns a random token for the client and the hash to store
+func newOpaqueToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", domain.Internal(fmt.Errorf("failed to generate token: %w", err))
+	}
+	value := base64.RawURLEncoding.EncodeToString(buf)
+	return value, hashToken(value), nil
+}
+
+// hashToken hashes a refresh or reset token for storage. The tokens are long
+// and random, so a fast hash is enough and allows lookups by hash.
+func hashToken(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
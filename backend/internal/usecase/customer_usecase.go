@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+	"github.com/modmastei2/Go-next/backend/internal/repository"
+)
+
+// CustomerUsecase defines the interface for customer business logic
+type CustomerUsecase interface {
+	CreateCustomer(ctx context.Context, req *domain.CreateCustomerRequest) (*domain.Customer, error)
+	GetCustomer(ctx context.Context, id uint) (*domain.Customer, error)
+	GetCustomerByEmail(ctx context.Context, email string) (*domain.Customer, error)
+	GetCustomers(ctx context.Context, page domain.PageRequest) (*domain.Page[domain.Customer], error)
+	UpdateCustomer(ctx context.Context, id uint, req *domain.UpdateCustomerRequest) (*domain.Customer, error)
+	DeleteCustomer(ctx context.Context, id uint) error
+	GetCustomerOrders(ctx context.Context, id uint, page domain.PageRequest) (*domain.Page[domain.Order], error)
+}
+
+// customerUsecase implements CustomerUsecase interface
+type customerUsecase struct {
+	customerRepo repository.CustomerRepository
+	orderRepo    repository.OrderRepository
+
+	maxPageLimit int
+}
+
+// NewCustomerUsecase creates a new customer usecase
+func NewCustomerUsecase(customerRepo repository.CustomerRepository, orderRepo repository.OrderRepository, maxPageLimit int) CustomerUsecase {
+	return &customerUsecase{
+		customerRepo: customerRepo,
+		orderRepo:    orderRepo,
+
+		maxPageLimit: maxPageLimit,
+	}
+}
+
+// CreateCustomer creates a new customer with a unique email address
+func (u *customerUsecase) CreateCustomer(ctx context.Context, req *domain.CreateCustomerRequest) (*domain.Customer, error) {
+	name, email, err := normalizeCustomer(req.Name, req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.ensureEmailAvailable(ctx, email, 0); err != nil {
+		return nil, err
+	}
+
+	customer := &domain.Customer{
+		Name:      name,
+		Email:     email,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := u.customerRepo.Create(ctx, customer); err != nil {
+		return nil, emailTaken(err)
+	}
+	return customer, nil
+}
+
+// GetCustomer retrieves a customer by ID
+func (u *customerUsecase) GetCustomer(ctx context.Context, id uint) (*domain.Customer, error) {
+	return u.customerRepo.GetByID(ctx, id)
+}
+
+// GetCustomerByEmail retrieves a customer by email address
+func (u *customerUsecase) GetCustomerByEmail(ctx context.Context, email string) (*domain.Customer, error) {
+	return u.customerRepo.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(email)))
+}
+
+// GetCustomers retrieves one page of customers
+func (u *customerUsecase) GetCustomers(ctx context.Context, page domain.PageRequest) (*domain.Page[domain.Customer], error) {
+	page.Clamp(u.maxPageLimit)
+	return u.customerRepo.GetAll(ctx, page)
+}
+
+// UpdateCustomer updates the name and email of a customer
+func (u *customerUsecase) UpdateCustomer(ctx context.Context, id uint, req *domain.UpdateCustomerRequest) (*domain.Customer, error) {
+	name, email, err := normalizeCustomer(req.Name, req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := u.customerRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.ensureEmailAvailable(ctx, email, customer.ID); err != nil {
+		return nil, err
+	}
+
+	customer.Name = name
+	customer.Email = email
+	customer.UpdatedAt = time.Now()
+	if err := u.customerRepo.Update(ctx, customer); err != nil {
+		return nil, emailTaken(err)
+	}
+	return customer, nil
+}
+
+// DeleteCustomer deletes a customer without orders
+func (u *customerUsecase) DeleteCustomer(ctx context.Context, id uint) error {
+	orders, err := u.orderRepo.GetByCustomerID(ctx, id, 1, 0)
+	if err != nil {
+		return err
+	}
+	if len(orders) > 0 {
+		return domain.ErrCustomerHasOrders
+	}
+
+	return u.customerRepo.Delete(ctx, id)
+}
+
+// GetCustomerOrders retrieves one page of a customer's orders, newest first
+func (u *customerUsecase) GetCustomerOrders(ctx context.Context, id uint, page domain.PageRequest) (*domain.Page[domain.Order], error) {
+	if _, err := u.customerRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	page.Clamp(u.maxPageLimit)
+	return u.orderRepo.GetAll(ctx, &domain.OrderQuery{
+		CustomerID:  id,
+		SortBy:      domain.OrderSortCreatedAt,
+		SortDesc:    true,
+		PageRequest: page,
+	})
+}
+
+// ensureEmailAvailable returns ErrEmailTaken if a customer other than ownerID uses the email
+func (u *customerUsecase) ensureEmailAvailable(ctx context.Context, email string, ownerID uint) error {
+	existing, err := u.customerRepo.GetByEmail(ctx, email)
+	if errors.Is(err, domain.ErrCustomerNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ID != ownerID {
+		return domain.ErrEmailTaken
+	}
+	return nil
+}
+
+// emailTaken reports a unique violation on customers as ErrEmailTaken, the email
+// is their only unique column. It covers writers racing ensureEmailAvailable.
+func emailTaken(err error) error {
+	if errors.Is(err, domain.ErrDuplicate) {
+		return domain.ErrEmailTaken
+	}
+	return err
+}
+
+// normalizeCustomer trims the name, lower-cases the email and validates both
+func normalizeCustomer(name, email string) (string, string, error) {
+	name = strings.TrimSpace(name)
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	if name == "" {
+		return "", "", domain.ErrValidation.Withf("name is required")
+	}
+	if address, err := mail.ParseAddress(email); err != nil || address.Address != email {
+		return "", "", domain.ErrValidation.Withf("invalid email address")
+	}
+	return name, email, nil
+}
@@ -1,119 +1,232 @@
 package usecase
 
 import (
+	"context"
 	"errors"
+	"strings"
+	"time"
+
 	"github.com/modmastei2/Go-next/backend/internal/domain"
 	"github.com/modmastei2/Go-next/backend/internal/repository"
-	"time"
 )
 
 // OrderUsecase defines the interface for order business logic
 type OrderUsecase interface {
-	CreateOrder(req *domain.CreateOrderRequest) (*domain.Order, error)
-	GetOrder(id uint) (*domain.Order, error)
-	GetOrders(limit, offset int) ([]domain.Order, error)
-	UpdateOrderStatus(id uint, status string) error
-	DeleteOrder(id uint) error
+	CreateOrder(ctx context.Context, req *domain.CreateOrderRequest) (*domain.Order, error)
+	GetOrder(ctx context.Context, id uint) (*domain.Order, error)
+	GetOrders(ctx context.Context, query *domain.OrderQuery) (*domain.Page[domain.Order], error)
+	UpdateOrderStatus(ctx context.Context, id uint, req *domain.UpdateOrderStatusRequest) error
+	GetOrderHistory(ctx context.Context, id uint) ([]domain.OrderStatusHistory, error)
+	DeleteOrder(ctx context.Context, id uint) error
+}
+
+// OrderMetrics records business events of orders
+type OrderMetrics interface {
+	OrderCreated(order *domain.Order)
+	StockRejected()
 }
 
 // orderUsecase implements OrderUsecase interface
 type orderUsecase struct {
 	orderRepo   repository.OrderRepository
 	productRepo repository.ProductRepository
+	historyRepo repository.OrderStatusHistoryRepository
+	txManager   repository.TxManager
+	metrics     OrderMetrics
+
+	maxPageLimit int
 }
 
 // NewOrderUsecase creates a new order usecase
-func NewOrderUsecase(orderRepo repository.OrderRepository, productRepo repository.ProductRepository) OrderUsecase {
+func NewOrderUsecase(orderRepo repository.OrderRepository, productRepo repository.ProductRepository, historyRepo repository.OrderStatusHistoryRepository, txManager repository.TxManager, metrics OrderMetrics, maxPageLimit int) OrderUsecase {
 	return &orderUsecase{
 		orderRepo:   orderRepo,
 		productRepo: productRepo,
+		historyRepo: historyRepo,
+		txManager:   txManager,
+		metrics:     metrics,
+
+		maxPageLimit: maxPageLimit,
 	}
 }
 
-// CreateOrder creates a new order with validation
-func (u *orderUsecase) CreateOrder(req *domain.CreateOrderRequest) (*domain.Order, error) {
-	// Validate and calculate total
-	var total float64
-	var orderItems []domain.OrderItem
+// CreateOrder creates a new order with validation.
+// Stock reservation and the order insert run in one transaction, so a failure
+// on any item leaves neither stock nor orders changed.
+func (u *orderUsecase) CreateOrder(ctx context.Context, req *domain.CreateOrderRequest) (*domain.Order, error) {
+	var order *domain.Order
+
+	err := u.txManager.WithinTransaction(ctx, func(repos *repository.Repositories) error {
+		if _, err := repos.Customers.GetByID(ctx, req.CustomerID); err != nil {
+			if errors.Is(err, domain.ErrCustomerNotFound) {
+				return domain.ErrUnknownCustomer.Withf("customer %d does not exist", req.CustomerID)
+			}
+			return err
+		}
 
-	for _, item := range req.Items {
-		product, err := u.productRepo.GetByID(item.ProductID)
-		if err != nil {
-			return nil, errors.New("product not found")
+		// Validate and calculate total
+		var total domain.Money
+		var orderItems []domain.OrderItem
+
+		for _, item := range req.Items {
+			product, err := repos.Products.GetByID(ctx, item.ProductID)
+			if errors.Is(err, domain.ErrProductNotFound) {
+				return domain.ErrUnknownProduct.Withf("product %d does not exist", item.ProductID)
+			}
+			if err != nil {
+				return err
+			}
+
+			// Reserve stock, the repository rejects the update if stock ran out
+			// since the product was read
+			if err := repos.Products.ReserveStock(ctx, product.ID, item.Quantity); err != nil {
+				if errors.Is(err, domain.ErrInsufficientStock) {
+					return domain.ErrInsufficientStock.Withf("insufficient stock for product: %s", product.Name)
+				}
+				return err
+			}
+
+			orderItem := domain.OrderItem{
+				ProductID: item.ProductID,
+				Quantity:  item.Quantity,
+				Price:     product.Price,
+			}
+			orderItems = append(orderItems, orderItem)
+
+			// Line totals are exact, an order may only mix items of one currency
+			lineTotal, err := product.Price.Mul(item.Quantity)
+			if err != nil {
+				return domain.ErrAmountOutOfRange.Withf("total of %d x %s is out of range", item.Quantity, product.Name)
+			}
+			if len(orderItems) == 1 {
+				total = lineTotal
+			} else if total, err = total.Add(lineTotal); errors.Is(err, domain.ErrAmountOutOfRange) {
+				return domain.ErrAmountOutOfRange.Withf("order total is out of range")
+			} else if err != nil {
+				return err
+			}
 		}
 
-		if product.Stock < item.Quantity {
-			return nil, errors.New("insufficient stock for product: " + product.Name)
+		// Create order
+		order = &domain.Order{
+			CustomerID: req.CustomerID,
+			Items:      orderItems,
+			Total:      total,
+			Status:     domain.OrderStatusPending,
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
 		}
 
-		orderItem := domain.OrderItem{
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     product.Price,
+		if err := repos.Orders.Create(ctx, order); err != nil {
+			return err
 		}
-		orderItems = append(orderItems, orderItem)
-		total += product.Price * float64(item.Quantity)
 
-		// Update stock
-		product.Stock -= item.Quantity
-		u.productRepo.Update(product)
-	}
+		err := repos.StatusHistory.Create(ctx, &domain.OrderStatusHistory{
+			OrderID:   order.ID,
+			ToStatus:  order.Status,
+			ChangedBy: req.CreatedBy,
+			Reason:    "order created",
+		})
+		if err != nil {
+			return err
+		}
 
-	// Create order
-	order := &domain.Order{
-		CustomerID: req.CustomerID,
-		Items:      orderItems,
-		Total:      total,
-		Status:     "pending",
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
+		// Reload the order with its customer and products, as GetOrder returns it
+		order, err = repos.Orders.GetByID(ctx, order.ID)
+		return err
+	})
+	if errors.Is(err, domain.ErrInsufficientStock) {
+		u.metrics.StockRejected()
 	}
-
-	err := u.orderRepo.Create(order)
 	if err != nil {
 		return nil, err
 	}
 
+	u.metrics.OrderCreated(order)
 	return order, nil
 }
 
 // GetOrder retrieves an order by ID
-func (u *orderUsecase) GetOrder(id uint) (*domain.Order, error) {
-	return u.orderRepo.GetByID(id)
+func (u *orderUsecase) GetOrder(ctx context.Context, id uint) (*domain.Order, error) {
+	return u.orderRepo.GetByID(ctx, id)
 }
 
-// GetOrders retrieves all orders with pagination
-func (u *orderUsecase) GetOrders(limit, offset int) ([]domain.Order, error) {
-	if limit <= 0 {
-		limit = 10
-	}
-	return u.orderRepo.GetAll(limit, offset)
+// GetOrders retrieves one page of orders matching the query
+func (u *orderUsecase) GetOrders(ctx context.Context, query *domain.OrderQuery) (*domain.Page[domain.Order], error) {
+	query.Clamp(u.maxPageLimit)
+	query.CustomerEmail = strings.ToLower(strings.TrimSpace(query.CustomerEmail))
+	return u.orderRepo.GetAll(ctx, query)
 }
 
-// UpdateOrderStatus updates the status of an order
-func (u *orderUsecase) UpdateOrderStatus(id uint, status string) error {
-	order, err := u.orderRepo.GetByID(id)
-	if err != nil {
-		return err
-	}
+// UpdateOrderStatus moves an order through its lifecycle and records the change.
+// The status is only saved if no concurrent request changed it since it was read,
+// so cancelling an order returns its reserved stock exactly once.
+func (u *orderUsecase) UpdateOrderStatus(ctx context.Context, id uint, req *domain.UpdateOrderStatusRequest) error {
+	return u.txManager.WithinTransaction(ctx, func(repos *repository.Repositories) error {
+		order, err := repos.Orders.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
 
-	validStatuses := map[string]bool{
-		"pending":    true,
-		"processing": true,
-		"completed":  true,
-		"cancelled":  true,
-	}
+		from := order.Status
+		if err := order.TransitionTo(req.Status, req.Reason); err != nil {
+			return err
+		}
 
-	if !validStatuses[status] {
-		return errors.New("invalid status")
-	}
+		if err := repos.Orders.UpdateStatus(ctx, order, from); err != nil {
+			return err
+		}
+
+		if order.Status.ReleasesStock() {
+			for _, item := range order.Items {
+				if err := repos.Products.ReleaseStock(ctx, item.ProductID, item.Quantity); err != nil {
+					return err
+				}
+			}
+		}
+
+		return repos.StatusHistory.Create(ctx, &domain.OrderStatusHistory{
+			OrderID:    order.ID,
+			FromStatus: from,
+			ToStatus:   order.Status,
+			ChangedBy:  req.ChangedBy,
+			Reason:     req.Reason,
+		})
+	})
+}
 
-	order.Status = status
-	order.UpdatedAt = time.Now()
-	return u.orderRepo.Update(order)
+// GetOrderHistory retrieves the status changes of an order
+func (u *orderUsecase) GetOrderHistory(ctx context.Context, id uint) ([]domain.OrderStatusHistory, error) {
+	if _, err := u.orderRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return u.historyRepo.GetByOrderID(ctx, id)
 }
 
-// DeleteOrder deletes an order
-func (u *orderUsecase) DeleteOrder(id uint) error {
-	return u.orderRepo.Delete(id)
+// DeleteOrder deletes an order with its items and status history.
+// Stock reserved by an order that is neither completed nor cancelled is
+// returned, as if the order had been cancelled first.
+func (u *orderUsecase) DeleteOrder(ctx context.Context, id uint) error {
+	return u.txManager.WithinTransaction(ctx, func(repos *repository.Repositories) error {
+		order, err := repos.Orders.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := repos.StatusHistory.DeleteByOrderID(ctx, order.ID); err != nil {
+			return err
+		}
+		if err := repos.Orders.Delete(ctx, order); err != nil {
+			return err
+		}
+
+		if !order.Status.IsFinal() {
+			for _, item := range order.Items {
+				if err := repos.Products.ReleaseStock(ctx, item.ProductID, item.Quantity); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
 }
@@ -0,0 +1,411 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modmastei2/Go-next/backend/config"
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+	"github.com/modmastei2/Go-next/backend/internal/repository"
+	"github.com/modmastei2/Go-next/backend/internal/usecase"
+	"github.com/modmastei2/Go-next/backend/pkg/database"
+	"github.com/modmastei2/Go-next/backend/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB opens and migrates a fresh SQLite database of the test
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := database.NewDatabase(&database.Config{
+		Driver:   database.DriverSQLite,
+		Database: filepath.Join(t.TempDir(), "test.db"),
+	})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.Logger = logger.Discard
+
+	if err := database.MigrateDatabase(db); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database handle: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+// openConcurrentTestDB opens the database of tests that race transactions
+// against each other: the server database named by TEST_DB_DRIVER and the
+// usual DB_* variables when set, SQLite otherwise. SQLite gets a pool of
+// connections instead of the server's single one, so the transactions really
+// run concurrently; each takes the write lock when it begins, which makes
+// them wait for one another rather than fail.
+func openConcurrentTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	driver := strings.ToLower(os.Getenv("TEST_DB_DRIVER"))
+	if driver == "" || strings.HasPrefix(driver, database.DriverSQLite) {
+		db := openTestDB(t)
+		sqlDB, err := db.DB()
+		if err != nil {
+			t.Fatalf("failed to get database handle: %v", err)
+		}
+		sqlDB.SetMaxOpenConns(20)
+		return db
+	}
+
+	cfg := config.Load().Database
+	cfg.Driver = driver
+	db, err := database.NewDatabase(&cfg)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.Logger = logger.Discard
+
+	if err := database.MigrateDatabase(db); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	// Stay below the server's connection limit when hundreds of goroutines run
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database handle: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(20)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+// uniqueEmail returns an email address no earlier run has used, server
+// databases keep the rows of previous runs
+func uniqueEmail(name string) string {
+	return fmt.Sprintf("%s-%d@example.com", name, time.Now().UnixNano())
+}
+
+func TestUpdateOrderStatusConcurrentCancelReleasesStockOnce(t *testing.T) {
+	const (
+		stock    = 10
+		quantity = 3
+		cancels  = 20
+	)
+
+	db := openConcurrentTestDB(t)
+
+	customer := domain.Customer{Name: "Cancel Test", Email: uniqueEmail("cancel")}
+	if err := db.Create(&customer).Error; err != nil {
+		t.Fatalf("failed to create customer: %v", err)
+	}
+	product := domain.Product{Name: "Cancelled Units", Price: domain.MustParseMoney("4.50", domain.DefaultCurrency), Stock: stock}
+	if err := db.Create(&product).Error; err != nil {
+		t.Fatalf("failed to create product: %v", err)
+	}
+
+	repos := repository.NewRepositories(db)
+	orderUsecase := usecase.NewOrderUsecase(repos.Orders, repos.Products, repos.StatusHistory, repository.NewTxManager(db), metrics.NewOrders(prometheus.NewRegistry()), 100)
+
+	order, err := orderUsecase.CreateOrder(t.Context(), &domain.CreateOrderRequest{
+		CustomerID: customer.ID,
+		Items:      []domain.OrderItemRequest{{ProductID: product.ID, Quantity: quantity}},
+	})
+	if err != nil {
+		t.Fatalf("failed to create order: %v", err)
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+		failures  []error
+	)
+
+	start := make(chan struct{})
+	for i := 0; i < cancels; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			err := orderUsecase.UpdateOrderStatus(t.Context(), order.ID, &domain.UpdateOrderStatusRequest{
+				Status: domain.OrderStatusCancelled,
+				Reason: "duplicate",
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, domain.ErrConflict), errors.Is(err, domain.ErrInvalidTransition):
+			default:
+				failures = append(failures, err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	for _, err := range failures {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	var remaining domain.Product
+	if err := db.First(&remaining, product.ID).Error; err != nil {
+		t.Fatalf("failed to reload product: %v", err)
+	}
+
+	var changes int64
+	if err := db.Model(&domain.OrderStatusHistory{}).Where("order_id = ?", order.ID).Count(&changes).Error; err != nil {
+		t.Fatalf("failed to count history: %v", err)
+	}
+
+	if succeeded != 1 || remaining.Stock != stock || changes != 2 {
+		t.Errorf("got %d cancels, %d stock and %d history rows; want 1, %d and 2",
+			succeeded, remaining.Stock, changes, stock)
+	}
+}
+
+// stockRace creates a product with the given stock and runs orders concurrent
+// attempts to buy one unit with reserve, all released at once. It returns how
+// many succeeded and the stock left; errors other than insufficient stock fail
+// the test.
+func stockRace(t *testing.T, db *gorm.DB, stock, orders int, reserve func(ctx context.Context, product *domain.Product) error) (succeeded, remaining int) {
+	t.Helper()
+
+	product := domain.Product{Name: "Last Units", Price: domain.MustParseMoney("9.99", domain.DefaultCurrency), Stock: stock}
+	if err := db.Create(&product).Error; err != nil {
+		t.Fatalf("failed to create product: %v", err)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		rejected int
+		failures []error
+	)
+
+	start := make(chan struct{})
+	for i := 0; i < orders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			err := reserve(t.Context(), &product)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, domain.ErrInsufficientStock):
+				rejected++
+			default:
+				failures = append(failures, err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	for _, err := range failures {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if succeeded+rejected != orders-len(failures) {
+		t.Errorf("got %d succeeded and %d rejected, want %d in total", succeeded, rejected, orders-len(failures))
+	}
+
+	var reloaded domain.Product
+	if err := db.First(&reloaded, product.ID).Error; err != nil {
+		t.Fatalf("failed to reload product: %v", err)
+	}
+	return succeeded, reloaded.Stock
+}
+
+func TestCreateOrderConcurrentNeverOversells(t *testing.T) {
+	const (
+		stock  = 50
+		orders = 300
+	)
+
+	db := openConcurrentTestDB(t)
+
+	customer := domain.Customer{Name: "Stress Test", Email: uniqueEmail("stress")}
+	if err := db.Create(&customer).Error; err != nil {
+		t.Fatalf("failed to create customer: %v", err)
+	}
+
+	repos := repository.NewRepositories(db)
+	orderUsecase := usecase.NewOrderUsecase(repos.Orders, repos.Products, repos.StatusHistory, repository.NewTxManager(db), metrics.NewOrders(prometheus.NewRegistry()), 100)
+
+	succeeded, remaining := stockRace(t, db, stock, orders, func(ctx context.Context, product *domain.Product) error {
+		_, err := orderUsecase.CreateOrder(ctx, &domain.CreateOrderRequest{
+			CustomerID: customer.ID,
+			Items:      []domain.OrderItemRequest{{ProductID: product.ID, Quantity: 1}},
+		})
+		return err
+	})
+
+	var created int64
+	if err := db.Model(&domain.Order{}).Where("customer_id = ?", customer.ID).Count(&created).Error; err != nil {
+		t.Fatalf("failed to count orders: %v", err)
+	}
+
+	if succeeded != stock || remaining != 0 || created != stock {
+		t.Errorf("got %d succeeded, %d orders and %d stock left; want %d, %d and 0",
+			succeeded, created, remaining, stock, stock)
+	}
+}
+
+func TestDeleteOrder(t *testing.T) {
+	const (
+		stock    = 10
+		quantity = 3
+	)
+
+	tests := []struct {
+		name      string
+		path      []domain.OrderStatus // statuses the order moves through before deletion
+		wantStock int
+	}{
+		{"pending", nil, stock},
+		{"processing", []domain.OrderStatus{domain.OrderStatusProcessing}, stock},
+		{"completed", []domain.OrderStatus{domain.OrderStatusProcessing, domain.OrderStatusCompleted}, stock - quantity},
+		{"cancelled", []domain.OrderStatus{domain.OrderStatusCancelled}, stock},
+	}
+
+	db := openTestDB(t)
+	customer := domain.Customer{Name: "Delete Test", Email: uniqueEmail("delete")}
+	if err := db.Create(&customer).Error; err != nil {
+		t.Fatalf("failed to create customer: %v", err)
+	}
+
+	repos := repository.NewRepositories(db)
+	orderUsecase := usecase.NewOrderUsecase(repos.Orders, repos.Products, repos.StatusHistory, repository.NewTxManager(db), metrics.NewOrders(prometheus.NewRegistry()), 100)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := domain.Product{Name: "Deleted Units", Price: domain.MustParseMoney("2.00", domain.DefaultCurrency), Stock: stock}
+			if err := db.Create(&product).Error; err != nil {
+				t.Fatalf("failed to create product: %v", err)
+			}
+
+			order, err := orderUsecase.CreateOrder(t.Context(), &domain.CreateOrderRequest{
+				CustomerID: customer.ID,
+				Items:      []domain.OrderItemRequest{{ProductID: product.ID, Quantity: quantity}},
+			})
+			if err != nil {
+				t.Fatalf("failed to create order: %v", err)
+			}
+			for _, status := range tt.path {
+				if err := orderUsecase.UpdateOrderStatus(t.Context(), order.ID, &domain.UpdateOrderStatusRequest{Status: status, Reason: "test"}); err != nil {
+					t.Fatalf("failed to change status to %s: %v", status, err)
+				}
+			}
+
+			if err := orderUsecase.DeleteOrder(t.Context(), order.ID); err != nil {
+				t.Fatalf("DeleteOrder failed: %v", err)
+			}
+
+			var remaining domain.Product
+			if err := db.First(&remaining, product.ID).Error; err != nil {
+				t.Fatalf("failed to reload product: %v", err)
+			}
+			if remaining.Stock != tt.wantStock {
+				t.Errorf("stock after delete = %d, want %d", remaining.Stock, tt.wantStock)
+			}
+
+			var items, history int64
+			db.Model(&domain.OrderItem{}).Where("order_id = ?", order.ID).Count(&items)
+			db.Model(&domain.OrderStatusHistory{}).Where("order_id = ?", order.ID).Count(&history)
+			if items != 0 || history != 0 {
+				t.Errorf("got %d items and %d history rows left, want none", items, history)
+			}
+
+			if err := orderUsecase.DeleteOrder(t.Context(), order.ID); !errors.Is(err, domain.ErrOrderNotFound) {
+				t.Errorf("second DeleteOrder = %v, want %v", err, domain.ErrOrderNotFound)
+			}
+		})
+	}
+}
+
+func TestCreateOrderRejectsTotalOutOfRange(t *testing.T) {
+	db := openTestDB(t)
+
+	customer := domain.Customer{Name: "Overflow Test", Email: uniqueEmail("overflow")}
+	if err := db.Create(&customer).Error; err != nil {
+		t.Fatalf("failed to create customer: %v", err)
+	}
+	product := domain.Product{Name: "Priceless", Price: domain.MustParseMoney("50000000000000000", domain.DefaultCurrency), Stock: 10}
+	if err := db.Create(&product).Error; err != nil {
+		t.Fatalf("failed to create product: %v", err)
+	}
+
+	repos := repository.NewRepositories(db)
+	orderUsecase := usecase.NewOrderUsecase(repos.Orders, repos.Products, repos.StatusHistory, repository.NewTxManager(db), metrics.NewOrders(prometheus.NewRegistry()), 100)
+
+	requests := map[string][]domain.OrderItemRequest{
+		"line total": {{ProductID: product.ID, Quantity: 2}},
+		"sum":        {{ProductID: product.ID, Quantity: 1}, {ProductID: product.ID, Quantity: 1}},
+	}
+	for name, items := range requests {
+		_, err := orderUsecase.CreateOrder(t.Context(), &domain.CreateOrderRequest{CustomerID: customer.ID, Items: items})
+		if !errors.Is(err, domain.ErrAmountOutOfRange) {
+			t.Errorf("%s: CreateOrder = %v, want %v", name, err, domain.ErrAmountOutOfRange)
+		}
+	}
+
+	var reloaded domain.Product
+	if err := db.First(&reloaded, product.ID).Error; err != nil {
+		t.Fatalf("failed to reload product: %v", err)
+	}
+	if reloaded.Stock != 10 {
+		t.Errorf("stock = %d after rejected orders, want 10", reloaded.Stock)
+	}
+}
+
+func TestCreateOrderReturnsCustomerAndProducts(t *testing.T) {
+	db := openTestDB(t)
+
+	customer := domain.Customer{Name: "Response Test", Email: uniqueEmail("response")}
+	if err := db.Create(&customer).Error; err != nil {
+		t.Fatalf("failed to create customer: %v", err)
+	}
+	product := domain.Product{Name: "Shown Units", Price: domain.MustParseMoney("3.25", domain.DefaultCurrency), Stock: 5}
+	if err := db.Create(&product).Error; err != nil {
+		t.Fatalf("failed to create product: %v", err)
+	}
+
+	repos := repository.NewRepositories(db)
+	orderUsecase := usecase.NewOrderUsecase(repos.Orders, repos.Products, repos.StatusHistory, repository.NewTxManager(db), metrics.NewOrders(prometheus.NewRegistry()), 100)
+
+	order, err := orderUsecase.CreateOrder(t.Context(), &domain.CreateOrderRequest{
+		CustomerID: customer.ID,
+		Items:      []domain.OrderItemRequest{{ProductID: product.ID, Quantity: 2}},
+	})
+	if err != nil {
+		t.Fatalf("failed to create order: %v", err)
+	}
+
+	if order.Customer.Email != customer.Email {
+		t.Errorf("order customer = %+v, want %s", order.Customer, customer.Email)
+	}
+	if len(order.Items) != 1 || order.Items[0].Product.Price != product.Price || order.Items[0].Product.Stock != 3 {
+		t.Errorf("order items = %+v, want the product with 3 units left", order.Items)
+	}
+	if want := domain.MustParseMoney("6.50", domain.DefaultCurrency); order.Total != want {
+		t.Errorf("order total = %v, want %v", order.Total, want)
+	}
+}
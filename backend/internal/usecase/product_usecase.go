@@ -1,55 +1,78 @@
 package usecase
 
 import (
+	"context"
+	"time"
+
 	"github.com/modmastei2/Go-next/backend/internal/domain"
 	"github.com/modmastei2/Go-next/backend/internal/repository"
 )
 
 // ProductUsecase defines the interface for product business logic
 type ProductUsecase interface {
-	CreateProduct(product *domain.Product) error
-	GetProduct(id uint) (*domain.Product, error)
-	GetProducts(limit, offset int) ([]domain.Product, error)
-	UpdateProduct(product *domain.Product) error
-	DeleteProduct(id uint) error
+	CreateProduct(ctx context.Context, req *domain.CreateProductRequest) (*domain.Product, error)
+	GetProduct(ctx context.Context, id uint) (*domain.Product, error)
+	GetProducts(ctx context.Context, query *domain.ProductQuery) (*domain.Page[domain.Product], error)
+	UpdateProduct(ctx context.Context, id uint, req *domain.UpdateProductRequest) (*domain.Product, error)
+	PatchProduct(ctx context.Context, id uint, req *domain.PatchProductRequest) (*domain.Product, error)
+	DeleteProduct(ctx context.Context, id uint) error
 }
 
 // productUsecase implements ProductUsecase interface
 type productUsecase struct {
-	productRepo repository.ProductRepository
+	productRepo  repository.ProductRepository
+	maxPageLimit int
 }
 
 // NewProductUsecase creates a new product usecase
-func NewProductUsecase(productRepo repository.ProductRepository) ProductUsecase {
+func NewProductUsecase(productRepo repository.ProductRepository, maxPageLimit int) ProductUsecase {
 	return &productUsecase{
-		productRepo: productRepo,
+		productRepo:  productRepo,
+		maxPageLimit: maxPageLimit,
 	}
 }
 
 // CreateProduct creates a new product
-func (u *productUsecase) CreateProduct(product *domain.Product) error {
-	return u.productRepo.Create(product)
+func (u *productUsecase) CreateProduct(ctx context.Context, req *domain.CreateProductRequest) (*domain.Product, error) {
+	product := &domain.Product{
+		Name:        req.Name,
+		Description: req.Description,
+		Price:       req.Price,
+		Stock:       req.Stock,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if err := u.productRepo.Create(ctx, product); err != nil {
+		return nil, err
+	}
+	return product, nil
 }
 
 // GetProduct retrieves a product by ID
-func (u *productUsecase) GetProduct(id uint) (*domain.Product, error) {
-	return u.productRepo.GetByID(id)
+func (u *productUsecase) GetProduct(ctx context.Context, id uint) (*domain.Product, error) {
+	return u.productRepo.GetByID(ctx, id)
 }
 
-// GetProducts retrieves all products with pagination
-func (u *productUsecase) GetProducts(limit, offset int) ([]domain.Product, error) {
-	if limit <= 0 {
-		limit = 10
-	}
-	return u.productRepo.GetAll(limit, offset)
+// GetProducts retrieves one page of products matching the query
+func (u *productUsecase) GetProducts(ctx context.Context, query *domain.ProductQuery) (*domain.Page[domain.Product], error) {
+	query.Clamp(u.maxPageLimit)
+	return u.productRepo.GetAll(ctx, query)
 }
 
-// UpdateProduct updates an existing product
-func (u *productUsecase) UpdateProduct(product *domain.Product) error {
-	return u.productRepo.Update(product)
+// UpdateProduct replaces all editable fields of a product
+func (u *productUsecase) UpdateProduct(ctx context.Context, id uint, req *domain.UpdateProductRequest) (*domain.Product, error) {
+	return u.PatchProduct(ctx, id, req.Patch())
+}
+
+// PatchProduct changes the fields set in the request and leaves the others untouched
+func (u *productUsecase) PatchProduct(ctx context.Context, id uint, req *domain.PatchProductRequest) (*domain.Product, error) {
+	if err := u.productRepo.Patch(ctx, id, req); err != nil {
+		return nil, err
+	}
+	return u.productRepo.GetByID(ctx, id)
 }
 
 // DeleteProduct deletes a product
-func (u *productUsecase) DeleteProduct(id uint) error {
-	return u.productRepo.Delete(id)
+func (u *productUsecase) DeleteProduct(ctx context.Context, id uint) error {
+	return u.productRepo.Delete(ctx, id)
 }
@@ -0,0 +1,310 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer creates the spans of usecase methods
+var tracer = otel.Tracer("github.com/modmastei2/Go-next/backend/internal/usecase")
+
+// endSpan records the outcome of a usecase method and ends its span. Domain
+// errors caused by the request, such as a missing order, are recorded but do
+// not mark the span as failed.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		var domainErr *domain.Error
+		if !errors.As(err, &domainErr) || domainErr.Kind == domain.KindInternal || domainErr.Kind == domain.KindTimeout {
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+	span.End()
+}
+
+// tracedOrderUsecase starts a span for each call of the wrapped order usecase
+type tracedOrderUsecase struct {
+	next OrderUsecase
+}
+
+// NewTracedOrderUsecase creates an order usecase that traces each call of next
+func NewTracedOrderUsecase(next OrderUsecase) OrderUsecase {
+	return &tracedOrderUsecase{next: next}
+}
+
+// CreateOrder traces OrderUsecase.CreateOrder
+func (u *tracedOrderUsecase) CreateOrder(ctx context.Context, req *domain.CreateOrderRequest) (*domain.Order, error) {
+	ctx, span := tracer.Start(ctx, "OrderUsecase.CreateOrder")
+	result, err := u.next.CreateOrder(ctx, req)
+	endSpan(span, err)
+	return result, err
+}
+
+// GetOrder traces OrderUsecase.GetOrder
+func (u *tracedOrderUsecase) GetOrder(ctx context.Context, id uint) (*domain.Order, error) {
+	ctx, span := tracer.Start(ctx, "OrderUsecase.GetOrder")
+	result, err := u.next.GetOrder(ctx, id)
+	endSpan(span, err)
+	return result, err
+}
+
+// GetOrders traces OrderUsecase.GetOrders
+func (u *tracedOrderUsecase) GetOrders(ctx context.Context, query *domain.OrderQuery) (*domain.Page[domain.Order], error) {
+	ctx, span := tracer.Start(ctx, "OrderUsecase.GetOrders")
+	result, err := u.next.GetOrders(ctx, query)
+	endSpan(span, err)
+	return result, err
+}
+
+// UpdateOrderStatus traces OrderUsecase.UpdateOrderStatus
+func (u *tracedOrderUsecase) UpdateOrderStatus(ctx context.Context, id uint, req *domain.UpdateOrderStatusRequest) error {
+	ctx, span := tracer.Start(ctx, "OrderUsecase.UpdateOrderStatus")
+	err := u.next.UpdateOrderStatus(ctx, id, req)
+	endSpan(span, err)
+	return err
+}
+
+// GetOrderHistory traces OrderUsecase.GetOrderHistory
+func (u *tracedOrderUsecase) GetOrderHistory(ctx context.Context, id uint) ([]domain.OrderStatusHistory, error) {
+	ctx, span := tracer.Start(ctx, "OrderUsecase.GetOrderHistory")
+	result, err := u.next.GetOrderHistory(ctx, id)
+	endSpan(span, err)
+	return result, err
+}
+
+// DeleteOrder traces OrderUsecase.DeleteOrder
+func (u *tracedOrderUsecase) DeleteOrder(ctx context.Context, id uint) error {
+	ctx, span := tracer.Start(ctx, "OrderUsecase.DeleteOrder")
+	err := u.next.DeleteOrder(ctx, id)
+	endSpan(span, err)
+	return err
+}
+
+// tracedProductUsecase starts a span for each call of the wrapped product usecase
+type tracedProductUsecase struct {
+	next ProductUsecase
+}
+
+// NewTracedProductUsecase creates a product usecase that traces each call of next
+func NewTracedProductUsecase(next ProductUsecase) ProductUsecase {
+	return &tracedProductUsecase{next: next}
+}
+
+// CreateProduct traces ProductUsecase.CreateProduct
+func (u *tracedProductUsecase) CreateProduct(ctx context.Context, req *domain.CreateProductRequest) (*domain.Product, error) {
+	ctx, span := tracer.Start(ctx, "ProductUsecase.CreateProduct")
+	result, err := u.next.CreateProduct(ctx, req)
+	endSpan(span, err)
+	return result, err
+}
+
+// GetProduct traces ProductUsecase.GetProduct
+func (u *tracedProductUsecase) GetProduct(ctx context.Context, id uint) (*domain.Product, error) {
+	ctx, span := tracer.Start(ctx, "ProductUsecase.GetProduct")
+	result, err := u.next.GetProduct(ctx, id)
+	endSpan(span, err)
+	return result, err
+}
+
+// GetProducts traces ProductUsecase.GetProducts
+func (u *tracedProductUsecase) GetProducts(ctx context.Context, query *domain.ProductQuery) (*domain.Page[domain.Product], error) {
+	ctx, span := tracer.Start(ctx, "ProductUsecase.GetProducts")
+	result, err := u.next.GetProducts(ctx, query)
+	endSpan(span, err)
+	return result, err
+}
+
+// UpdateProduct traces ProductUsecase.UpdateProduct
+func (u *tracedProductUsecase) UpdateProduct(ctx context.Context, id uint, req *domain.UpdateProductRequest) (*domain.Product, error) {
+	ctx, span := tracer.Start(ctx, "ProductUsecase.UpdateProduct")
+	result, err := u.next.UpdateProduct(ctx, id, req)
+	endSpan(span, err)
+	return result, err
+}
+
+// PatchProduct traces ProductUsecase.PatchProduct
+func (u *tracedProductUsecase) PatchProduct(ctx context.Context, id uint, req *domain.PatchProductRequest) (*domain.Product, error) {
+	ctx, span := tracer.Start(ctx, "ProductUsecase.PatchProduct")
+	result, err := u.next.PatchProduct(ctx, id, req)
+	endSpan(span, err)
+	return result, err
+}
+
+// DeleteProduct traces ProductUsecase.DeleteProduct
+func (u *tracedProductUsecase) DeleteProduct(ctx context.Context, id uint) error {
+	ctx, span := tracer.Start(ctx, "ProductUsecase.DeleteProduct")
+	err := u.next.DeleteProduct(ctx, id)
+	endSpan(span, err)
+	return err
+}
+
+// tracedCustomerUsecase starts a span for each call of the wrapped customer usecase
+type tracedCustomerUsecase struct {
+	next CustomerUsecase
+}
+
+// NewTracedCustomerUsecase creates a customer usecase that traces each call of next
+func NewTracedCustomerUsecase(next CustomerUsecase) CustomerUsecase {
+	return &tracedCustomerUsecase{next: next}
+}
+
+// CreateCustomer traces CustomerUsecase.CreateCustomer
+func (u *tracedCustomerUsecase) CreateCustomer(ctx context.Context, req *domain.CreateCustomerRequest) (*domain.Customer, error) {
+	ctx, span := tracer.Start(ctx, "CustomerUsecase.CreateCustomer")
+	result, err := u.next.CreateCustomer(ctx, req)
+	endSpan(span, err)
+	return result, err
+}
+
+// GetCustomer traces CustomerUsecase.GetCustomer
+func (u *tracedCustomerUsecase) GetCustomer(ctx context.Context, id uint) (*domain.Customer, error) {
+	ctx, span := tracer.Start(ctx, "CustomerUsecase.GetCustomer")
+	result, err := u.next.GetCustomer(ctx, id)
+	endSpan(span, err)
+	return result, err
+}
+
+// GetCustomerByEmail traces CustomerUsecase.GetCustomerByEmail
+func (u *tracedCustomerUsecase) GetCustomerByEmail(ctx context.Context, email string) (*domain.Customer, error) {
+	ctx, span := tracer.Start(ctx, "CustomerUsecase.GetCustomerByEmail")
+	result, err := u.next.GetCustomerByEmail(ctx, email)
+	endSpan(span, err)
+	return result, err
+}
+
+// GetCustomers traces CustomerUsecase.GetCustomers
+func (u *tracedCustomerUsecase) GetCustomers(ctx context.Context, page domain.PageRequest) (*domain.Page[domain.Customer], error) {
+	ctx, span := tracer.Start(ctx, "CustomerUsecase.GetCustomers")
+	result, err := u.next.GetCustomers(ctx, page)
+	endSpan(span, err)
+	return result, err
+}
+
+// UpdateCustomer traces CustomerUsecase.UpdateCustomer
+func (u *tracedCustomerUsecase) UpdateCustomer(ctx context.Context, id uint, req *domain.UpdateCustomerRequest) (*domain.Customer, error) {
+	ctx, span := tracer.Start(ctx, "CustomerUsecase.UpdateCustomer")
+	result, err := u.next.UpdateCustomer(ctx, id, req)
+	endSpan(span, err)
+	return result, err
+}
+
+// DeleteCustomer traces CustomerUsecase.DeleteCustomer
+func (u *tracedCustomerUsecase) DeleteCustomer(ctx context.Context, id uint) error {
+	ctx, span := tracer.Start(ctx, "CustomerUsecase.DeleteCustomer")
+	err := u.next.DeleteCustomer(ctx, id)
+	endSpan(span, err)
+	return err
+}
+
+// GetCustomerOrders traces CustomerUsecase.GetCustomerOrders
+func (u *tracedCustomerUsecase) GetCustomerOrders(ctx context.Context, id uint, page domain.PageRequest) (*domain.Page[domain.Order], error) {
+	ctx, span := tracer.Start(ctx, "CustomerUsecase.GetCustomerOrders")
+	result, err := u.next.GetCustomerOrders(ctx, id, page)
+	endSpan(span, err)
+	return result, err
+}
+
+// tracedAuthUsecase starts a span for each call of the wrapped auth usecase
+type tracedAuthUsecase struct {
+	next AuthUsecase
+}
+
+// NewTracedAuthUsecase creates an auth usecase that traces each call of next
+func NewTracedAuthUsecase(next AuthUsecase) AuthUsecase {
+	return &tracedAuthUsecase{next: next}
+}
+
+// Register traces AuthUsecase.Register
+func (u *tracedAuthUsecase) Register(ctx context.Context, req *domain.RegisterRequest) (*domain.Customer, error) {
+	ctx, span := tracer.Start(ctx, "AuthUsecase.Register")
+	result, err := u.next.Register(ctx, req)
+	endSpan(span, err)
+	return result, err
+}
+
+// Login traces AuthUsecase.Login
+func (u *tracedAuthUsecase) Login(ctx context.Context, req *domain.LoginRequest) (*domain.TokenResponse, error) {
+	ctx, span := tracer.Start(ctx, "AuthUsecase.Login")
+	result, err := u.next.Login(ctx, req)
+	endSpan(span, err)
+	return result, err
+}
+
+// Refresh traces AuthUsecase.Refresh
+func (u *tracedAuthUsecase) Refresh(ctx context.Context, refreshToken string) (*domain.TokenResponse, error) {
+	ctx, span := tracer.Start(ctx, "AuthUsecase.Refresh")
+	result, err := u.next.Refresh(ctx, refreshToken)
+	endSpan(span, err)
+	return result, err
+}
+
+// Logout traces AuthUsecase.Logout
+func (u *tracedAuthUsecase) Logout(ctx context.Context, refreshToken string) error {
+	ctx, span := tracer.Start(ctx, "AuthUsecase.Logout")
+	err := u.next.Logout(ctx, refreshToken)
+	endSpan(span, err)
+	return err
+}
+
+// RequestPasswordReset traces AuthUsecase.RequestPasswordReset
+func (u *tracedAuthUsecase) RequestPasswordReset(ctx context.Context, req *domain.PasswordResetRequest) error {
+	ctx, span := tracer.Start(ctx, "AuthUsecase.RequestPasswordReset")
+	err := u.next.RequestPasswordReset(ctx, req)
+	endSpan(span, err)
+	return err
+}
+
+// ResetPassword traces AuthUsecase.ResetPassword
+func (u *tracedAuthUsecase) ResetPassword(ctx context.Context, req *domain.ConfirmPasswordResetRequest) error {
+	ctx, span := tracer.Start(ctx, "AuthUsecase.ResetPassword")
+	err := u.next.ResetPassword(ctx, req)
+	endSpan(span, err)
+	return err
+}
+
+// tracedAPIKeyUsecase starts a span for each call of the wrapped API key usecase
+type tracedAPIKeyUsecase struct {
+	next APIKeyUsecase
+}
+
+// NewTracedAPIKeyUsecase creates an API key usecase that traces each call of next
+func NewTracedAPIKeyUsecase(next APIKeyUsecase) APIKeyUsecase {
+	return &tracedAPIKeyUsecase{next: next}
+}
+
+// CreateAPIKey traces APIKeyUsecase.CreateAPIKey
+func (u *tracedAPIKeyUsecase) CreateAPIKey(ctx context.Context, req *domain.CreateAPIKeyRequest) (*domain.CreatedAPIKey, error) {
+	ctx, span := tracer.Start(ctx, "APIKeyUsecase.CreateAPIKey")
+	result, err := u.next.CreateAPIKey(ctx, req)
+	endSpan(span, err)
+	return result, err
+}
+
+// GetAPIKeys traces APIKeyUsecase.GetAPIKeys
+func (u *tracedAPIKeyUsecase) GetAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	ctx, span := tracer.Start(ctx, "APIKeyUsecase.GetAPIKeys")
+	result, err := u.next.GetAPIKeys(ctx)
+	endSpan(span, err)
+	return result, err
+}
+
+// RevokeAPIKey traces APIKeyUsecase.RevokeAPIKey
+func (u *tracedAPIKeyUsecase) RevokeAPIKey(ctx context.Context, id uint) error {
+	ctx, span := tracer.Start(ctx, "APIKeyUsecase.RevokeAPIKey")
+	err := u.next.RevokeAPIKey(ctx, id)
+	endSpan(span, err)
+	return err
+}
+
+// Authenticate traces APIKeyUsecase.Authenticate
+func (u *tracedAPIKeyUsecase) Authenticate(ctx context.Context, key string) (*domain.Principal, error) {
+	ctx, span := tracer.Start(ctx, "APIKeyUsecase.Authenticate")
+	result, err := u.next.Authenticate(ctx, key)
+	endSpan(span, err)
+	return result, err
+}
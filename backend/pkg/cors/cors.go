@@ -0,0 +1,72 @@
+package cors
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Config holds the Cross-Origin Resource Sharing policy
+type Config struct {
+	AllowOrigins     []string // exact origins, "https://*.example.com" for any subdomain or "*" for any origin
+	AllowMethods     []string
+	AllowHeaders     []string
+	ExposeHeaders    []string // response headers browsers may read, such as Link
+	AllowCredentials bool     // allow cookies and Authorization headers, not with "*"
+	MaxAge           time.Duration
+}
+
+// Validate checks the policy for mistakes browsers would reject or that would expose the API
+func (cfg Config) Validate() error {
+	for _, origin := range cfg.AllowOrigins {
+		if origin == "*" {
+			if cfg.AllowCredentials {
+				return errors.New(`CORS cannot allow credentials for any origin "*", list the origins instead`)
+			}
+			continue
+		}
+
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.Path != "" {
+			return fmt.Errorf("invalid CORS origin %q, want scheme://host[:port]", origin)
+		}
+		if strings.Contains(strings.TrimPrefix(u.Host, "*."), "*") {
+			return fmt.Errorf("invalid CORS origin %q, only a leading *. subdomain wildcard is supported", origin)
+		}
+	}
+	return nil
+}
+
+// AnyOrigin reports whether the policy allows every origin with "*"
+func (cfg Config) AnyOrigin() bool {
+	for _, origin := range cfg.AllowOrigins {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsOrigin reports whether an origin is allowed. "https://*.example.com"
+// matches subdomains at any depth over https but not example.com itself.
+func (cfg Config) AllowsOrigin(origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, pattern := range cfg.AllowOrigins {
+		pattern = strings.ToLower(pattern)
+		if pattern == "*" || pattern == origin {
+			return true
+		}
+
+		scheme, host, ok := strings.Cut(pattern, "://*.")
+		if !ok || !strings.HasPrefix(origin, scheme+"://") {
+			continue
+		}
+		subdomain, found := strings.CutSuffix(strings.TrimPrefix(origin, scheme+"://"), "."+host)
+		if found && subdomain != "" && !strings.ContainsAny(subdomain, "/:@?#") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package cors_test
+
+import (
+	"testing"
+
+	"github.com/modmastei2/Go-next/backend/pkg/cors"
+)
+
+func TestAllowsOrigin(t *testing.T) {
+	policy := cors.Config{AllowOrigins: []string{"http://localhost:3000", "https://*.example.com", "HTTPS://Shop.Test"}}
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"http://localhost:3000", true},
+		{"http://localhost:3001", false},
+		{"https://localhost:3000", false},
+		{"https://shop.test", true},
+		{"https://app.example.com", true},
+		{"https://a.b.example.com", true},
+		{"https://APP.Example.com", true},
+		{"https://example.com", false},
+		{"http://app.example.com", false},
+		{"https://app.example.com:8443", false},
+		{"https://evil.com/.example.com", false},
+		{"https://user@evil.com?.example.com", false},
+		{"https://app.example.com.evil.com", false},
+		{"https://appexample.com", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := policy.AllowsOrigin(tt.origin); got != tt.want {
+			t.Errorf("AllowsOrigin(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+
+	anyOrigin := cors.Config{AllowOrigins: []string{"*"}}
+	if !anyOrigin.AnyOrigin() || !anyOrigin.AllowsOrigin("https://anywhere.test") {
+		t.Errorf(`"*" does not allow every origin`)
+	}
+	if policy.AnyOrigin() {
+		t.Errorf("AnyOrigin() = true for a list of origins")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     cors.Config
+		wantErr bool
+	}{
+		{"exact origins", cors.Config{AllowOrigins: []string{"http://localhost:3000", "https://shop.test"}, AllowCredentials: true}, false},
+		{"subdomain wildcard", cors.Config{AllowOrigins: []string{"https://*.example.com"}, AllowCredentials: true}, false},
+		{"any origin", cors.Config{AllowOrigins: []string{"*"}}, false},
+		{"any origin with credentials", cors.Config{AllowOrigins: []string{"*"}, AllowCredentials: true}, true},
+		{"any origin among others with credentials", cors.Config{AllowOrigins: []string{"https://shop.test", "*"}, AllowCredentials: true}, true},
+		{"missing scheme", cors.Config{AllowOrigins: []string{"shop.test"}}, true},
+		{"other scheme", cors.Config{AllowOrigins: []string{"ftp://shop.test"}}, true},
+		{"with path", cors.Config{AllowOrigins: []string{"https://shop.test/app"}}, true},
+		{"inner wildcard", cors.Config{AllowOrigins: []string{"https://api.*.example.com"}}, true},
+		{"wildcard port", cors.Config{AllowOrigins: []string{"https://example.com:*"}}, true},
+	}
+
+	for _, tt := range tests {
+		err := tt.cfg.Validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/modmastei2/Go-next/backend/pkg/migrate"
+	"gorm.io/gorm"
+)
+
+// Snapshot models of the initial schema. Migrations keep their own copies of
+// the models so later changes to internal/domain do not rewrite history.
+
+type customerV1 struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"size:255"`
+	Email     string `gorm:"size:255;unique"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (customerV1) TableName() string { return "customers" }
+
+type productV1 struct {
+	ID          uint   `gorm:"primaryKey"`
+	Name        string `gorm:"size:255"`
+	Description string
+	Price       float64
+	Stock       int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (productV1) TableName() string { return "products" }
+
+type orderV1 struct {
+	ID         uint `gorm:"primaryKey"`
+	CustomerID uint
+	Customer   customerV1 `gorm:"foreignKey:CustomerID"`
+	Total      float64
+	Status     string `gorm:"size:50"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (orderV1) TableName() string { return "orders" }
+
+type orderItemV1 struct {
+	ID        uint `gorm:"primaryKey"`
+	OrderID   uint
+	Order     orderV1 `gorm:"foreignKey:OrderID"`
+	ProductID uint
+	Product   productV1 `gorm:"foreignKey:ProductID"`
+	Quantity  int
+	Price     float64
+}
+
+func (orderItemV1) TableName() string { return "order_items" }
+
+// createInitialSchema creates the tables previously managed by AutoMigrate.
+// Tables that already exist are left untouched so databases created before
+// versioned migrations can adopt them without manual steps.
+var createInitialSchema = migrate.Migration{
+	Version: 1,
+	Name:    "create_initial_schema",
+	Up: func(tx *gorm.DB) error {
+		for _, model := range []interface{}{&customerV1{}, &productV1{}, &orderV1{}, &orderItemV1{}} {
+			if tx.Migrator().HasTable(model) {
+				continue
+			}
+			if err := tx.Migrator().CreateTable(model); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&orderItemV1{}, &orderV1{}, &productV1{}, &customerV1{})
+	},
+}
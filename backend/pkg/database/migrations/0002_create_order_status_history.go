@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/modmastei2/Go-next/backend/pkg/migrate"
+	"gorm.io/gorm"
+)
+
+type orderStatusHistoryV2 struct {
+	ID         uint    `gorm:"primaryKey"`
+	OrderID    uint    `gorm:"index"`
+	Order      orderV1 `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+	FromStatus string  `gorm:"size:50"`
+	ToStatus   string  `gorm:"size:50"`
+	ChangedBy  string  `gorm:"size:255"`
+	Reason     string  `gorm:"size:500"`
+	CreatedAt  time.Time
+}
+
+func (orderStatusHistoryV2) TableName() string { return "order_status_history" }
+
+// createOrderStatusHistory adds the status audit trail and seeds it with the
+// current status of existing orders.
+var createOrderStatusHistory = migrate.Migration{
+	Version: 2,
+	Name:    "create_order_status_history",
+	Up: func(tx *gorm.DB) error {
+		if err := tx.Migrator().CreateTable(&orderStatusHistoryV2{}); err != nil {
+			return err
+		}
+		return tx.Exec(`INSERT INTO order_status_history (order_id, from_status, to_status, changed_by, reason, created_at)
+			SELECT id, '', status, 'system', 'status before history tracking', created_at FROM orders`).Error
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&orderStatusHistoryV2{})
+	},
+}
@@ -0,0 +1,98 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/modmastei2/Go-next/backend/pkg/migrate"
+	"gorm.io/gorm"
+)
+
+type productMoneyV3 struct {
+	PriceAmount   int64  `gorm:"not null;default:0"`
+	PriceCurrency string `gorm:"size:3;not null;default:USD"`
+}
+
+func (productMoneyV3) TableName() string { return "products" }
+
+type orderItemMoneyV3 struct {
+	PriceAmount   int64  `gorm:"not null;default:0"`
+	PriceCurrency string `gorm:"size:3;not null;default:USD"`
+}
+
+func (orderItemMoneyV3) TableName() string { return "order_items" }
+
+type orderMoneyV3 struct {
+	TotalAmount   int64  `gorm:"not null;default:0"`
+	TotalCurrency string `gorm:"size:3;not null;default:USD"`
+}
+
+func (orderMoneyV3) TableName() string { return "orders" }
+
+// moneyColumn describes a float column converted to amount and currency columns
+type moneyColumn struct {
+	model      interface{}
+	legacy     interface{}
+	table      string
+	float      string
+	floatField string
+	amount     string
+	currency   string
+}
+
+var moneyColumns = []moneyColumn{
+	{&productMoneyV3{}, &productV1{}, "products", "price", "Price", "PriceAmount", "PriceCurrency"},
+	{&orderItemMoneyV3{}, &orderItemV1{}, "order_items", "price", "Price", "PriceAmount", "PriceCurrency"},
+	{&orderMoneyV3{}, &orderV1{}, "orders", "total", "Total", "TotalAmount", "TotalCurrency"},
+}
+
+// convertMoneyColumns replaces the float64 price and total columns with an
+// integer amount in minor units plus an ISO 4217 currency code. Existing
+// amounts were always dollars, so they become USD cents rounded to the
+// nearest cent. Down converts back assuming two-decimal currencies.
+var convertMoneyColumns = migrate.Migration{
+	Version: 3,
+	Name:    "convert_money_columns",
+	Up: func(tx *gorm.DB) error {
+		for _, column := range moneyColumns {
+			if err := tx.Migrator().AddColumn(column.model, column.amount); err != nil {
+				return err
+			}
+			if err := tx.Migrator().AddColumn(column.model, column.currency); err != nil {
+				return err
+			}
+
+			amount := tx.NamingStrategy.ColumnName("", column.amount)
+			convert := fmt.Sprintf("UPDATE %s SET %s = ROUND(%s * 100, 0) WHERE %s IS NOT NULL",
+				column.table, amount, column.float, column.float)
+			if err := tx.Exec(convert).Error; err != nil {
+				return err
+			}
+
+			if err := dropColumn(tx, column.legacy, column.floatField); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, column := range moneyColumns {
+			if err := tx.Migrator().AddColumn(column.legacy, column.floatField); err != nil {
+				return err
+			}
+
+			amount := tx.NamingStrategy.ColumnName("", column.amount)
+			convert := fmt.Sprintf("UPDATE %s SET %s = %s / 100.0", column.table, column.float, amount)
+			if err := tx.Exec(convert).Error; err != nil {
+				return err
+			}
+
+			if err := dropColumn(tx, column.model, column.amount); err != nil {
+				return err
+			}
+			if err := dropColumn(tx, column.model, column.currency); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
@@ -0,0 +1,73 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/modmastei2/Go-next/backend/pkg/migrate"
+	"gorm.io/gorm"
+)
+
+type orderIndexesV4 struct {
+	CustomerID  uint      `gorm:"index:idx_orders_customer_id"`
+	Status      string    `gorm:"size:50;index:idx_orders_status_created_at,priority:1"`
+	CreatedAt   time.Time `gorm:"index:idx_orders_status_created_at,priority:2;index:idx_orders_created_at"`
+	TotalAmount int64     `gorm:"index:idx_orders_total_amount"`
+}
+
+func (orderIndexesV4) TableName() string { return "orders" }
+
+type orderItemIndexesV4 struct {
+	OrderID   uint `gorm:"index:idx_order_items_order_id"`
+	ProductID uint `gorm:"index:idx_order_items_product_id"`
+}
+
+func (orderItemIndexesV4) TableName() string { return "order_items" }
+
+var orderSearchIndexes = []struct {
+	model interface{}
+	name  string
+}{
+	{&orderIndexesV4{}, "idx_orders_customer_id"},
+	{&orderIndexesV4{}, "idx_orders_status_created_at"},
+	{&orderIndexesV4{}, "idx_orders_created_at"},
+	{&orderIndexesV4{}, "idx_orders_total_amount"},
+	{&orderItemIndexesV4{}, "idx_order_items_order_id"},
+	{&orderItemIndexesV4{}, "idx_order_items_product_id"},
+}
+
+// addOrderSearchIndexes indexes the columns the admin order search filters and
+// sorts on. Databases created by AutoMigrate have an unbounded status column,
+// which SQL Server and MySQL cannot index, so it is narrowed first.
+var addOrderSearchIndexes = migrate.Migration{
+	Version: 4,
+	Name:    "add_order_search_indexes",
+	Up: func(tx *gorm.DB) error {
+		switch tx.Dialector.Name() {
+		case "sqlserver", "mysql":
+			if err := tx.Migrator().AlterColumn(&orderIndexesV4{}, "Status"); err != nil {
+				return err
+			}
+		}
+
+		for _, index := range orderSearchIndexes {
+			if tx.Migrator().HasIndex(index.model, index.name) {
+				continue
+			}
+			if err := tx.Migrator().CreateIndex(index.model, index.name); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, index := range orderSearchIndexes {
+			if !tx.Migrator().HasIndex(index.model, index.name) {
+				continue
+			}
+			if err := tx.Migrator().DropIndex(index.model, index.name); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
@@ -0,0 +1,56 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/modmastei2/Go-next/backend/pkg/migrate"
+	"gorm.io/gorm"
+)
+
+type credentialV5 struct {
+	ID           uint       `gorm:"primaryKey"`
+	CustomerID   uint       `gorm:"uniqueIndex"`
+	Customer     customerV1 `gorm:"foreignKey:CustomerID;constraint:OnDelete:CASCADE"`
+	PasswordHash string     `gorm:"size:255"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (credentialV5) TableName() string { return "credentials" }
+
+type refreshTokenV5 struct {
+	ID         uint       `gorm:"primaryKey"`
+	CustomerID uint       `gorm:"index"`
+	Customer   customerV1 `gorm:"foreignKey:CustomerID;constraint:OnDelete:CASCADE"`
+	TokenHash  string     `gorm:"size:64;uniqueIndex"`
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+func (refreshTokenV5) TableName() string { return "refresh_tokens" }
+
+type passwordResetTokenV5 struct {
+	ID         uint       `gorm:"primaryKey"`
+	CustomerID uint       `gorm:"index"`
+	Customer   customerV1 `gorm:"foreignKey:CustomerID;constraint:OnDelete:CASCADE"`
+	TokenHash  string     `gorm:"size:64;uniqueIndex"`
+	ExpiresAt  time.Time
+	UsedAt     *time.Time
+	CreatedAt  time.Time
+}
+
+func (passwordResetTokenV5) TableName() string { return "password_reset_tokens" }
+
+// createAccountTables adds customer passwords, refresh tokens and password
+// reset tokens. They are deleted together with their customer.
+var createAccountTables = migrate.Migration{
+	Version: 5,
+	Name:    "create_account_tables",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().CreateTable(&credentialV5{}, &refreshTokenV5{}, &passwordResetTokenV5{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&passwordResetTokenV5{}, &refreshTokenV5{}, &credentialV5{})
+	},
+}
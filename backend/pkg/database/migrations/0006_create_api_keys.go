@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/modmastei2/Go-next/backend/pkg/migrate"
+	"gorm.io/gorm"
+)
+
+type apiKeyV6 struct {
+	ID         uint   `gorm:"primaryKey"`
+	Name       string `gorm:"size:255"`
+	Prefix     string `gorm:"size:16"`
+	KeyHash    string `gorm:"size:64;uniqueIndex"`
+	Scopes     string `gorm:"size:500"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedBy  string `gorm:"size:255"`
+	CreatedAt  time.Time
+}
+
+func (apiKeyV6) TableName() string { return "api_keys" }
+
+// createAPIKeys adds hashed API keys for service-to-service integrations
+var createAPIKeys = migrate.Migration{
+	Version: 6,
+	Name:    "create_api_keys",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().CreateTable(&apiKeyV6{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&apiKeyV6{})
+	},
+}
@@ -0,0 +1,55 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/modmastei2/Go-next/backend/pkg/migrate"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// All returns every schema migration of the application in version order.
+// Migrations are append-only: never edit one that has shipped, add a new one instead.
+func All() []migrate.Migration {
+	return []migrate.Migration{
+		createInitialSchema,
+		createOrderStatusHistory,
+		convertMoneyColumns,
+		addOrderSearchIndexes,
+		createAccountTables,
+		createAPIKeys,
+	}
+}
+
+// dropColumn drops the column of a model field. SQL Server refuses to drop a
+// column that still has a default constraint, so that constraint is removed
+// first. The SQLite migrator rebuilds the whole table, which fails on foreign
+// keys inside a transaction, so the native DROP COLUMN is used instead.
+func dropColumn(tx *gorm.DB, model interface{}, field string) error {
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(model); err != nil {
+		return err
+	}
+	column := stmt.Schema.LookUpField(field)
+	if column == nil {
+		return fmt.Errorf("unknown field %s on %s", field, stmt.Table)
+	}
+
+	switch tx.Dialector.Name() {
+	case "sqlserver":
+		err := tx.Exec(`DECLARE @constraint sysname
+			SELECT @constraint = dc.name FROM sys.default_constraints dc
+			JOIN sys.columns c ON c.default_object_id = dc.object_id
+			WHERE dc.parent_object_id = OBJECT_ID(@table) AND c.name = @column
+			IF @constraint IS NOT NULL EXEC('ALTER TABLE ' + @table + ' DROP CONSTRAINT ' + @constraint)`,
+			sql.Named("table", stmt.Table), sql.Named("column", column.DBName)).Error
+		if err != nil {
+			return err
+		}
+	case "sqlite":
+		return tx.Exec("ALTER TABLE ? DROP COLUMN ?",
+			clause.Table{Name: stmt.Table}, clause.Column{Name: column.DBName}).Error
+	}
+	return tx.Migrator().DropColumn(model, field)
+}
@@ -0,0 +1,185 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/modmastei2/Go-next/backend/pkg/logging"
+)
+
+// Status is the state of a component or of the whole service
+type Status string
+
+// Statuses
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+// Checker checks one dependency such as the database, an error means it is unavailable
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a function to the Checker interface
+type CheckerFunc func(ctx context.Context) error
+
+// Check calls f
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// unavailableError is a check failure with a reason that is safe to report
+type unavailableError struct {
+	reason string
+	err    error
+}
+
+// Unavailable returns a check failure reported with reason, err is only logged and may be nil
+func Unavailable(reason string, err error) error {
+	return &unavailableError{reason: reason, err: err}
+}
+
+// Error returns the reason followed by the cause, if any
+func (e *unavailableError) Error() string {
+	if e.err != nil {
+		return e.reason + ": " + e.err.Error()
+	}
+	return e.reason
+}
+
+// Unwrap returns the cause
+func (e *unavailableError) Unwrap() error {
+	return e.err
+}
+
+// Component is the result of one check. Errors are only logged, the report
+// gives "timeout", the reason of an Unavailable error or "failed", so it can
+// be served publicly.
+type Component struct {
+	Status    Status  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Build describes the running binary
+type Build struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+// NewBuild returns the build of the running binary, with the commit recorded
+// by the Go toolchain when built from a git checkout
+func NewBuild(version string) Build {
+	build := Build{Version: version}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return build
+	}
+
+	build.GoVersion = info.GoVersion
+	modified := false
+	for _, setting := range info.Settings {
+		switch {
+		case setting.Key == "vcs.revision" && len(setting.Value) >= 12:
+			build.Commit = setting.Value[:12]
+		case setting.Key == "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if modified && build.Commit != "" {
+		build.Commit += "-dirty"
+	}
+	return build
+}
+
+// Report is the readiness of the service, it is up when every component is up
+type Report struct {
+	Status     Status               `json:"status"`
+	Build      Build                `json:"build"`
+	Components map[string]Component `json:"components"`
+}
+
+// namedChecker is a registered checker
+type namedChecker struct {
+	name    string
+	checker Checker
+}
+
+// Registry runs the registered checkers concurrently, each with a timeout
+type Registry struct {
+	build   Build
+	timeout time.Duration
+
+	mu       sync.RWMutex
+	checkers []namedChecker
+}
+
+// NewRegistry creates a registry whose checks must finish within timeout
+func NewRegistry(build Build, timeout time.Duration) *Registry {
+	return &Registry{build: build, timeout: timeout}
+}
+
+// Register adds a checker reported under name
+func (r *Registry) Register(name string, checker Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkers = append(r.checkers, namedChecker{name: name, checker: checker})
+}
+
+// Check runs every checker and aggregates the results
+func (r *Registry) Check(ctx context.Context) Report {
+	r.mu.RLock()
+	checkers := r.checkers
+	r.mu.RUnlock()
+
+	report := Report{Status: StatusUp, Build: r.build, Components: make(map[string]Component, len(checkers))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, c := range checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			component := r.run(ctx, c)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Components[c.name] = component
+			if component.Status != StatusUp {
+				report.Status = StatusDown
+			}
+		}()
+	}
+	wg.Wait()
+	return report
+}
+
+// run runs one checker with the timeout and logs why it failed
+func (r *Registry) run(ctx context.Context, c namedChecker) Component {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.checker.Check(ctx)
+	component := Component{Status: StatusUp, LatencyMS: logging.Milliseconds(time.Since(start))}
+	if err == nil {
+		return component
+	}
+
+	component.Status = StatusDown
+	var unavailable *unavailableError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded):
+		component.Error = "timeout"
+	case errors.As(err, &unavailable):
+		component.Error = unavailable.reason
+	default:
+		component.Error = "failed"
+	}
+	logging.FromContext(ctx).Warn("Health check failed", "component", c.name, "error", err)
+	return component
+}
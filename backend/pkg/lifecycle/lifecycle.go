@@ -0,0 +1,74 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Lifecycle tracks whether the server takes traffic and releases its resources
+// on shutdown
+type Lifecycle struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	draining atomic.Bool
+
+	mu      sync.Mutex
+	closers []closer
+}
+
+// closer releases a resource such as the database pool
+type closer struct {
+	name  string
+	close func() error
+}
+
+// New creates a lifecycle for a starting server
+func New() *Lifecycle {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Lifecycle{ctx: ctx, cancel: cancel}
+}
+
+// Context is canceled when the server stops. Requests derive their contexts
+// from it, so work still running at that point is canceled.
+func (l *Lifecycle) Context() context.Context {
+	return l.ctx
+}
+
+// Ready reports whether the server takes new traffic, false once draining started
+func (l *Lifecycle) Ready() bool {
+	return !l.draining.Load()
+}
+
+// Drain marks the server as not ready so load balancers stop sending traffic
+func (l *Lifecycle) Drain() {
+	l.draining.Store(true)
+}
+
+// OnStop registers a function releasing a resource on shutdown. Resources are released in the reverse order of registration.
+func (l *Lifecycle) OnStop(name string, close func() error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closers = append(l.closers, closer{name: name, close: close})
+}
+
+// Stop cancels the lifecycle context and then releases the resources
+func (l *Lifecycle) Stop() error {
+	l.Drain()
+	l.cancel()
+
+	l.mu.Lock()
+	closers := l.closers
+	l.closers = nil
+	l.mu.Unlock()
+
+	var errs []error
+	for i := len(closers) - 1; i >= 0; i-- {
+		if err := closers[i].close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close %s: %w", closers[i].name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// GormLogger writes GORM logs through slog with the logger of the query's
+// context, so SQL run with a request context carries the request ID.
+// Statements are logged at debug level, slow ones as warnings and failed
+// ones as errors.
+type GormLogger struct {
+	level         logger.LogLevel
+	slowThreshold time.Duration
+}
+
+// NewGormLogger creates a GORM logger warning about queries slower than slowThreshold
+func NewGormLogger(slowThreshold time.Duration) *GormLogger {
+	return &GormLogger{level: logger.Info, slowThreshold: slowThreshold}
+}
+
+// LogMode returns a copy of the logger with the given GORM level
+func (l *GormLogger) LogMode(level logger.LogLevel) logger.Interface {
+	copied := *l
+	copied.level = level
+	return &copied
+}
+
+// Info logs an informational message
+func (l *GormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Info {
+		FromContext(ctx).InfoContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+// Warn logs a warning
+func (l *GormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Warn {
+		FromContext(ctx).WarnContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+// Error logs an error
+func (l *GormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Error {
+		FromContext(ctx).ErrorContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+// Trace logs a SQL statement once it has run
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+
+	log := FromContext(ctx)
+	elapsed := time.Since(begin)
+	attrs := func() []interface{} {
+		sql, rows := fc()
+		return []interface{}{slog.String("sql", sql), slog.Int64("rows", rows), slog.Float64("duration_ms", Milliseconds(elapsed))}
+	}
+
+	switch {
+	case err != nil && l.level >= logger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		log.ErrorContext(ctx, "SQL query failed", append(attrs(), slog.Any("error", err))...)
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= logger.Warn:
+		log.WarnContext(ctx, "Slow SQL query", append(attrs(), slog.Float64("threshold_ms", Milliseconds(l.slowThreshold)))...)
+	case l.level >= logger.Info && log.Enabled(ctx, slog.LevelDebug):
+		log.DebugContext(ctx, "SQL query", attrs()...)
+	}
+}
@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Supported output formats
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Config selects the log format and the lowest level written
+type Config struct {
+	Format string // json for production, text for development
+	Level  string // debug, info, warn or error
+}
+
+// New creates a logger writing to w
+func New(cfg Config, w io.Writer) (*slog.Logger, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q, want debug, info, warn or error", cfg.Level)
+	}
+	options := &slog.HandlerOptions{Level: level}
+
+	switch strings.ToLower(cfg.Format) {
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, options)), nil
+	case FormatText:
+		return slog.New(slog.NewTextHandler(w, options)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q, want json or text", cfg.Format)
+	}
+}
+
+// contextKey is the context key of the request-scoped logger
+type contextKey struct{}
+
+// WithContext returns a context carrying the logger
+func WithContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger of the context, such as one carrying the
+// request ID, or the default logger
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+			return logger
+		}
+	}
+	return slog.Default()
+}
+
+// Milliseconds converts a duration to fractional milliseconds for log fields
+func Milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
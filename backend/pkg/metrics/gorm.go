@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+)
+
+// startKey is the statement setting holding the start time of a query
+const startKey = "metrics:start"
+
+// GormPlugin records the duration of every GORM operation by operation and table
+type GormPlugin struct {
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+}
+
+// NewGormPlugin creates and registers the query metrics, install it with db.Use
+func NewGormPlugin(reg prometheus.Registerer) *GormPlugin {
+	p := &GormPlugin{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "gorm_query_duration_seconds",
+			Help:    "Duration of database operations by operation and table.",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation", "table"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gorm_query_errors_total",
+			Help: "Failed database operations by operation and table, missing records excluded.",
+		}, []string{"operation", "table"}),
+	}
+	reg.MustRegister(p.duration, p.errors)
+	return p
+}
+
+// Name implements gorm.Plugin
+func (p *GormPlugin) Name() string {
+	return "metrics"
+}
+
+// Initialize implements gorm.Plugin by timing each callback chain
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	chains := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", callbacks.Create().Before("gorm:create").Register, callbacks.Create().After("gorm:create").Register},
+		{"query", callbacks.Query().Before("gorm:query").Register, callbacks.Query().After("gorm:query").Register},
+		{"update", callbacks.Update().Before("gorm:update").Register, callbacks.Update().After("gorm:update").Register},
+		{"delete", callbacks.Delete().Before("gorm:delete").Register, callbacks.Delete().After("gorm:delete").Register},
+		{"row", callbacks.Row().Before("gorm:row").Register, callbacks.Row().After("gorm:row").Register},
+		{"raw", callbacks.Raw().Before("gorm:raw").Register, callbacks.Raw().After("gorm:raw").Register},
+	}
+	for _, chain := range chains {
+		if err := chain.before("metrics:before_"+chain.operation, start); err != nil {
+			return err
+		}
+		if err := chain.after("metrics:after_"+chain.operation, p.observe(chain.operation)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// start remembers when a statement started
+func start(db *gorm.DB) {
+	db.InstanceSet(startKey, time.Now())
+}
+
+// observe returns a callback recording the duration of an operation
+func (p *GormPlugin) observe(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(startKey)
+		if !ok {
+			return
+		}
+		started, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+
+		table := db.Statement.Table
+		p.duration.WithLabelValues(operation, table).Observe(time.Since(started).Seconds())
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			p.errors.WithLabelValues(operation, table).Inc()
+		}
+	}
+}
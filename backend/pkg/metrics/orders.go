@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/modmastei2/Go-next/backend/internal/domain"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Orders holds the business metrics of orders
+type Orders struct {
+	created       *prometheus.CounterVec
+	value         *prometheus.HistogramVec
+	stockRejected prometheus.Counter
+}
+
+// NewOrders creates and registers the order metrics
+func NewOrders(reg prometheus.Registerer) *Orders {
+	m := &Orders{
+		created: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "shop_orders_created_total",
+			Help: "Orders created by currency.",
+		}, []string{"currency"}),
+		value: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "shop_order_value",
+			Help:    "Total of created orders in major currency units by currency.",
+			Buckets: []float64{10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000},
+		}, []string{"currency"}),
+		stockRejected: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "shop_stock_out_rejections_total",
+			Help: "Orders rejected because a product did not have enough stock.",
+		}),
+	}
+	reg.MustRegister(m.created, m.value, m.stockRejected)
+	return m
+}
+
+// OrderCreated records a created order and its total
+func (m *Orders) OrderCreated(order *domain.Order) {
+	m.created.WithLabelValues(order.Total.Currency).Inc()
+	if value, err := strconv.ParseFloat(order.Total.Decimal(), 64); err == nil {
+		m.value.WithLabelValues(order.Total.Currency).Observe(value)
+	}
+}
+
+// StockRejected records an order rejected for insufficient stock
+func (m *Orders) StockRejected() {
+	m.stockRejected.Inc()
+}
@@ -0,0 +1,123 @@
+package migrate
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	// lockID is the single row that represents the migration lock
+	lockID = 1
+	// lockPollInterval is how often a waiting instance retries the lock
+	lockPollInterval = 500 * time.Millisecond
+	// lockHeartbeatInterval is how often the holder refreshes the lock timestamp
+	lockHeartbeatInterval = time.Minute
+	// staleLockAfter is when a lock that stopped being refreshed, e.g. by a
+	// crashed instance, is taken over
+	staleLockAfter = 5 * lockHeartbeatInterval
+)
+
+// lock is the row of the schema_migrations_lock table held while migrating
+type lock struct {
+	ID       uint   `gorm:"primaryKey;autoIncrement:false"`
+	LockedBy string `gorm:"size:255"`
+	LockedAt time.Time
+}
+
+// TableName overrides the table name used by lock
+func (lock) TableName() string {
+	return "schema_migrations_lock"
+}
+
+// withLock runs fn while holding the migration lock so only one instance migrates.
+// The lock is a row inserted under a fixed primary key, which works on every
+// supported database without relying on dialect specific advisory locks.
+func (m *Migrator) withLock(fn func() error) error {
+	if err := createTableIfMissing(m.db, &lock{}); err != nil {
+		return err
+	}
+	if err := m.ensureTable(); err != nil {
+		return err
+	}
+
+	if err := m.acquire(); err != nil {
+		return err
+	}
+	defer m.release()
+
+	stop := m.heartbeat()
+	defer stop()
+
+	return fn()
+}
+
+// acquire blocks until the lock row is inserted or LockTimeout expires.
+// While another instance migrates every insert fails, the polls run without
+// the query logger so waiting does not log an error twice a second.
+func (m *Migrator) acquire() error {
+	poll := m.db.Session(&gorm.Session{Logger: logger.Discard})
+	deadline := time.Now().Add(m.LockTimeout)
+	for {
+		err := poll.Create(&lock{ID: lockID, LockedBy: m.owner, LockedAt: time.Now().UTC()}).Error
+		if err == nil {
+			return nil
+		}
+
+		var holder lock
+		if poll.First(&holder, lockID).Error == nil && time.Since(holder.LockedAt) > staleLockAfter {
+			slog.Warn("Releasing stale migration lock", "locked_by", holder.LockedBy, "locked_at", holder.LockedAt)
+			m.db.Where("id = ? AND locked_by = ?", lockID, holder.LockedBy).Delete(&lock{})
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			if holder.LockedBy == "" {
+				return fmt.Errorf("timed out waiting for migration lock: %w", err)
+			}
+			return fmt.Errorf("timed out waiting for migration lock held by %s", holder.LockedBy)
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// heartbeat refreshes the lock timestamp until the returned function is called,
+// so a migration running longer than staleLockAfter is not taken over
+func (m *Migrator) heartbeat() func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(lockHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				err := m.db.Model(&lock{}).
+					Where("id = ? AND locked_by = ?", lockID, m.owner).
+					Update("locked_at", time.Now().UTC()).Error
+				if err != nil {
+					slog.Warn("Failed to refresh migration lock", "error", err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// release deletes the lock row if this migrator still owns it
+func (m *Migrator) release() {
+	err := m.db.Where("id = ? AND locked_by = ?", lockID, m.owner).Delete(&lock{}).Error
+	if err != nil {
+		slog.Error("Failed to release migration lock", "error", err)
+	}
+}
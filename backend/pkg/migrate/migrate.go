@@ -0,0 +1,303 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Migration is a single versioned, reversible schema change
+type Migration struct {
+	Version uint
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// Status describes whether a migration has been applied
+type Status struct {
+	Version   uint       `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// Plan holds the SQL a pending migration would execute
+type Plan struct {
+	Migration  Migration
+	Statements []string
+}
+
+// record is a row of the schema_migrations table
+type record struct {
+	Version   uint   `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"size:255"`
+	AppliedAt time.Time
+}
+
+// TableName overrides the table name used by record
+func (record) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrator applies and reverts migrations and tracks them in schema_migrations
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+	owner      string
+
+	// LockTimeout is how long Up and Down wait for another instance to finish
+	LockTimeout time.Duration
+}
+
+// New creates a migrator for the given migrations, which must have unique versions
+func New(db *gorm.DB, migrations []Migration) (*Migrator, error) {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+
+	for i, migration := range sorted {
+		if migration.Version == 0 || migration.Up == nil || migration.Down == nil {
+			return nil, fmt.Errorf("migration %d %q must have a version, Up and Down", migration.Version, migration.Name)
+		}
+		if i > 0 && sorted[i-1].Version == migration.Version {
+			return nil, fmt.Errorf("duplicate migration version %d", migration.Version)
+		}
+	}
+
+	hostname, _ := os.Hostname()
+	return &Migrator{
+		db:          db,
+		migrations:  sorted,
+		owner:       fmt.Sprintf("%s:%d:%d", hostname, os.Getpid(), time.Now().UnixNano()),
+		LockTimeout: 2 * time.Minute,
+	}, nil
+}
+
+// Up applies all pending migrations in order and returns how many were applied
+func (m *Migrator) Up() (int, error) {
+	var count int
+	err := m.withLock(func() error {
+		pending, err := m.pending()
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range pending {
+			if err := m.apply(migration); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Down reverts the last steps applied migrations and returns how many were reverted
+func (m *Migrator) Down(steps int) (int, error) {
+	var count int
+	err := m.withLock(func() error {
+		applied, err := m.applied()
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && count < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if err := m.revert(migration); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Status reports every known migration and whether it has been applied
+func (m *Migrator) Status() ([]Status, error) {
+	if err := m.ensureTable(); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if rec, ok := applied[migration.Version]; ok {
+			appliedAt := rec.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Pending returns the migrations that have not been applied yet. It only reads,
+// so all migrations are pending while schema_migrations does not exist.
+func (m *Migrator) Pending() ([]Migration, error) {
+	return m.pending()
+}
+
+// DryRun returns the SQL each pending migration would execute without applying it.
+// Pending migrations run inside a transaction that is always rolled back, so this
+// is only available on databases with transactional DDL.
+func (m *Migrator) DryRun() ([]Plan, error) {
+	if m.db.Dialector.Name() == "mysql" {
+		return nil, errors.New("dry-run is not supported on mysql because DDL statements commit implicitly")
+	}
+
+	var plans []Plan
+	errRollback := errors.New("dry-run rollback")
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		pending, err := m.pendingIn(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range pending {
+			recorder := &recorder{Interface: logger.Discard}
+			if err := migration.Up(tx.Session(&gorm.Session{Logger: recorder})); err != nil {
+				return fmt.Errorf("migration %d %s: %w", migration.Version, migration.Name, err)
+			}
+			plans = append(plans, Plan{Migration: migration, Statements: recorder.statements})
+		}
+		return errRollback
+	})
+
+	if err != nil && !errors.Is(err, errRollback) {
+		return nil, err
+	}
+	return plans, nil
+}
+
+// apply runs a migration and records it in a single transaction
+func (m *Migrator) apply(migration Migration) error {
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		if err := migration.Up(tx); err != nil {
+			return err
+		}
+		return tx.Create(&record{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			AppliedAt: time.Now().UTC(),
+		}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply migration %d %s: %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+// revert rolls back a migration and removes its record in a single transaction
+func (m *Migrator) revert(migration Migration) error {
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		if err := migration.Down(tx); err != nil {
+			return err
+		}
+		return tx.Delete(&record{}, migration.Version).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revert migration %d %s: %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+// pending returns the migrations without a schema_migrations record
+func (m *Migrator) pending() ([]Migration, error) {
+	return m.pendingIn(m.db)
+}
+
+// pendingIn returns the pending migrations as seen by the given handle
+func (m *Migrator) pendingIn(db *gorm.DB) ([]Migration, error) {
+	if !db.Migrator().HasTable(&record{}) {
+		return m.migrations, nil
+	}
+
+	var records []record
+	if err := db.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+
+	applied := make(map[uint]bool, len(records))
+	for _, rec := range records {
+		applied[rec.Version] = true
+	}
+
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if !applied[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// applied returns the schema_migrations records keyed by version
+func (m *Migrator) applied() (map[uint]record, error) {
+	var records []record
+	if err := m.db.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+
+	applied := make(map[uint]record, len(records))
+	for _, rec := range records {
+		applied[rec.Version] = rec
+	}
+	return applied, nil
+}
+
+// ensureTable creates the schema_migrations table if it does not exist
+func (m *Migrator) ensureTable() error {
+	return createTableIfMissing(m.db, &record{})
+}
+
+// createTableIfMissing creates a bookkeeping table, tolerating a concurrent creator
+func createTableIfMissing(db *gorm.DB, model interface{}) error {
+	if db.Migrator().HasTable(model) {
+		return nil
+	}
+	if err := db.Migrator().CreateTable(model); err != nil && !db.Migrator().HasTable(model) {
+		return fmt.Errorf("failed to create migration table: %w", err)
+	}
+	return nil
+}
+
+// recorder is a GORM logger that collects executed statements for dry runs
+type recorder struct {
+	logger.Interface
+	statements []string
+}
+
+// LogMode keeps the recorder regardless of the requested level
+func (r *recorder) LogMode(logger.LogLevel) logger.Interface {
+	return r
+}
+
+// Trace records every statement except the migrator's introspection queries
+func (r *recorder) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	keyword := strings.ToUpper(strings.SplitN(strings.TrimSpace(sql), " ", 2)[0])
+	if keyword == "SELECT" || keyword == "PRAGMA" {
+		return
+	}
+	r.statements = append(r.statements, sql)
+}
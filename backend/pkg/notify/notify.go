@@ -0,0 +1,92 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/modmastei2/Go-next/backend/pkg/logging"
+)
+
+// Supported notifier drivers
+const (
+	DriverLog  = "log"
+	DriverFile = "file"
+)
+
+// Config selects how messages are delivered
+type Config struct {
+	Driver string // log or file
+	File   string // file messages are appended to, file driver only
+}
+
+// Message is a message for a user, such as a password reset link
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Notifier delivers messages to users. Implementations for email or SMS can
+// be plugged in without touching the code that sends messages.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// New creates the notifier selected by the configuration
+func New(cfg Config) (Notifier, error) {
+	switch cfg.Driver {
+	case DriverLog:
+		return NewLogNotifier(), nil
+	case DriverFile:
+		if cfg.File == "" {
+			return nil, fmt.Errorf("the file notifier requires a file path")
+		}
+		return NewFileNotifier(cfg.File), nil
+	default:
+		return nil, fmt.Errorf("unsupported notifier %q", cfg.Driver)
+	}
+}
+
+// logNotifier writes messages to the application log, for local development
+type logNotifier struct{}
+
+// NewLogNotifier creates a notifier that logs messages
+func NewLogNotifier() Notifier {
+	return logNotifier{}
+}
+
+// Notify logs the message
+func (logNotifier) Notify(ctx context.Context, msg Message) error {
+	logging.FromContext(ctx).Info("Notification", "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+	return nil
+}
+
+// fileNotifier appends messages to a file, for local development and tests
+type fileNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileNotifier creates a notifier that appends messages to a file
+func NewFileNotifier(path string) Notifier {
+	return &fileNotifier{path: path}
+}
+
+// Notify appends the message to the file
+func (n *fileNotifier) Notify(ctx context.Context, msg Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	file, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open notification file: %w", err)
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n",
+		time.Now().Format(time.RFC1123Z), msg.To, msg.Subject, msg.Body)
+	return err
+}
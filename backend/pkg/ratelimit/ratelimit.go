@@ -0,0 +1,155 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limit allows Requests requests per Period. Requests is also the burst size:
+// an idle client may send that many at once, after which tokens refill evenly.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseLimit parses a limit such as "100/1m" or "5/s"
+func ParseLimit(value string) (Limit, error) {
+	requests, period, ok := strings.Cut(strings.TrimSpace(value), "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, want requests/period such as 100/1m", value)
+	}
+
+	n, err := strconv.Atoi(requests)
+	if err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("invalid request count in rate limit %q", value)
+	}
+
+	// Allow a bare unit such as "s" for one second
+	if period != "" && (period[0] < '0' || period[0] > '9') {
+		period = "1" + period
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("invalid period in rate limit %q", value)
+	}
+	return Limit{Requests: n, Period: d}, nil
+}
+
+// Result is the outcome of taking a token from a bucket
+type Result struct {
+	Allowed    bool
+	Remaining  int           // whole tokens left in the bucket
+	RetryAfter time.Duration // time until the next token, set when not allowed
+	Reset      time.Duration // time until the bucket is full again
+}
+
+// Store keeps the token buckets. The in-memory store suits a single instance;
+// replicas behind a load balancer need a shared implementation, e.g. on Redis.
+type Store interface {
+	Take(key string, limit Limit) (Result, error)
+	// Peek reports whether Take would succeed without taking a token
+	Peek(key string, limit Limit) (Result, error)
+}
+
+// bucket is a token bucket, tokens are refilled lazily when it is used
+type bucket struct {
+	tokens   float64
+	capacity float64
+	rate     float64 // tokens per second
+	updated  time.Time
+}
+
+// refill adds the tokens earned since the last update
+func (b *bucket) refill(now time.Time) {
+	b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.updated).Seconds()*b.rate)
+	b.updated = now
+}
+
+// MemoryStore is a Store holding the buckets in process memory
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// sweepInterval is how often buckets that refilled completely are dropped
+const sweepInterval = time.Minute
+
+// NewMemoryStore creates an in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), lastSweep: time.Now(), now: time.Now}
+}
+
+// Take takes a token from the bucket of key if one is available
+func (s *MemoryStore) Take(key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	capacity := float64(limit.Requests)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		s.buckets[key] = b
+	}
+	b.capacity, b.rate = capacity, capacity/limit.Period.Seconds()
+	b.refill(now)
+
+	result := Result{Allowed: b.tokens >= 1}
+	if result.Allowed {
+		b.tokens--
+	} else {
+		result.RetryAfter = secondsToDuration((1 - b.tokens) / b.rate)
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = secondsToDuration((capacity - b.tokens) / b.rate)
+
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now)
+	}
+	return result, nil
+}
+
+// Peek reports whether the bucket of key has a token left without taking it
+func (s *MemoryStore) Peek(key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	capacity := float64(limit.Requests)
+	b, ok := s.buckets[key]
+	if !ok {
+		return Result{Allowed: true, Remaining: limit.Requests}, nil
+	}
+	b.capacity, b.rate = capacity, capacity/limit.Period.Seconds()
+	b.refill(s.now())
+
+	result := Result{Allowed: b.tokens >= 1, Remaining: int(b.tokens)}
+	if !result.Allowed {
+		result.RetryAfter = secondsToDuration((1 - b.tokens) / b.rate)
+	}
+	result.Reset = secondsToDuration((capacity - b.tokens) / b.rate)
+	return result, nil
+}
+
+// sweep drops buckets that have refilled completely, a new bucket starts full
+// anyway, so memory only grows with clients that were recently active
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		b.refill(now)
+		if b.tokens >= b.capacity {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+// secondsToDuration converts fractional seconds to a duration
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
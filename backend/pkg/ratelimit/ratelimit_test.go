@@ -0,0 +1,140 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestStore returns a store whose clock only moves when the returned
+// function advances it
+func newTestStore() (*MemoryStore, func(time.Duration)) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	store.lastSweep = now
+	return store, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Limit
+	}{
+		{"100/1m", Limit{Requests: 100, Period: time.Minute}},
+		{"5/s", Limit{Requests: 5, Period: time.Second}},
+		{"10/h", Limit{Requests: 10, Period: time.Hour}},
+		{" 30/90s ", Limit{Requests: 30, Period: 90 * time.Second}},
+		{"1/1m30s", Limit{Requests: 1, Period: 90 * time.Second}},
+	}
+	for _, tt := range tests {
+		got, err := ParseLimit(tt.value)
+		if err != nil {
+			t.Errorf("ParseLimit(%q) failed: %v", tt.value, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseLimit(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+
+	for _, value := range []string{"", "100", "/1m", "0/1m", "-1/1m", "x/1m", "10/", "10/0s", "10/-1m", "10/fortnight"} {
+		if _, err := ParseLimit(value); err == nil {
+			t.Errorf("ParseLimit(%q) succeeded, want an error", value)
+		}
+	}
+}
+
+func TestMemoryStoreBurst(t *testing.T) {
+	store, _ := newTestStore()
+	limit := Limit{Requests: 3, Period: time.Minute}
+
+	for i := 2; i >= 0; i-- {
+		result, _ := store.Take("client", limit)
+		if !result.Allowed || result.Remaining != i {
+			t.Fatalf("burst request: got %+v, want allowed with %d remaining", result, i)
+		}
+	}
+
+	result, _ := store.Take("client", limit)
+	if result.Allowed {
+		t.Fatalf("request after the burst was allowed")
+	}
+	// One token refills every 20 seconds
+	if result.RetryAfter != 20*time.Second || result.Reset != time.Minute {
+		t.Errorf("got retry after %v and reset %v, want 20s and 1m", result.RetryAfter, result.Reset)
+	}
+
+	// Buckets are per key
+	if result, _ := store.Take("other", limit); !result.Allowed {
+		t.Errorf("another client was limited")
+	}
+}
+
+func TestMemoryStoreRefill(t *testing.T) {
+	store, advance := newTestStore()
+	limit := Limit{Requests: 3, Period: time.Minute}
+
+	for i := 0; i < 3; i++ {
+		store.Take("client", limit)
+	}
+
+	advance(19 * time.Second)
+	if result, _ := store.Take("client", limit); result.Allowed {
+		t.Fatalf("request before a token refilled was allowed")
+	}
+
+	advance(time.Second)
+	if result, _ := store.Take("client", limit); !result.Allowed {
+		t.Fatalf("request after a token refilled was rejected")
+	}
+
+	// Refilling stops at the capacity
+	advance(time.Hour)
+	result, _ := store.Take("client", limit)
+	if !result.Allowed || result.Remaining != 2 {
+		t.Errorf("after an idle hour got %+v, want allowed with 2 remaining", result)
+	}
+}
+
+func TestMemoryStorePeek(t *testing.T) {
+	store, _ := newTestStore()
+	limit := Limit{Requests: 1, Period: time.Minute}
+
+	if result, _ := store.Peek("client", limit); !result.Allowed || result.Remaining != 1 {
+		t.Fatalf("Peek of a new client = %+v, want allowed with 1 remaining", result)
+	}
+	if len(store.buckets) != 0 {
+		t.Errorf("Peek created a bucket")
+	}
+
+	// Peeking takes nothing
+	store.Peek("client", limit)
+	if result, _ := store.Take("client", limit); !result.Allowed {
+		t.Fatalf("Take after Peek was rejected")
+	}
+	if result, _ := store.Peek("client", limit); result.Allowed || result.RetryAfter != time.Minute {
+		t.Errorf("Peek of an empty bucket = %+v, want rejected with retry after 1m", result)
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	store, advance := newTestStore()
+	limit := Limit{Requests: 10, Period: time.Minute}
+
+	store.Take("idle", limit)
+	advance(30 * time.Second)
+	for i := 0; i < 10; i++ {
+		store.Take("busy", limit)
+	}
+
+	// The idle bucket has refilled by the next sweep, the busy one has not
+	advance(sweepInterval - 30*time.Second)
+	store.Take("trigger", limit)
+
+	if _, ok := store.buckets["idle"]; ok {
+		t.Errorf("full bucket was not swept")
+	}
+	if _, ok := store.buckets["busy"]; !ok {
+		t.Errorf("partly used bucket was swept")
+	}
+}
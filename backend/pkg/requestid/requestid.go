@@ -0,0 +1,100 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+// Header is the HTTP header carrying the request ID
+const Header = "X-Request-ID"
+
+// maxLength bounds inbound request IDs, which end up in every log line
+const maxLength = 128
+
+// New returns a UUIDv7: a millisecond timestamp followed by 74 random bits,
+// so IDs sort by creation time and do not collide across instances
+func New() string {
+	var id [16]byte
+	binary.BigEndian.PutUint64(id[:8], uint64(time.Now().UnixMilli())<<16)
+	mustRead(id[6:])
+	id[6] = id[6]&0x0f | 0x70 // version 7
+	id[8] = id[8]&0x3f | 0x80 // RFC 9562 variant
+
+	buf := make([]byte, 36)
+	hex.Encode(buf[0:8], id[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], id[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], id[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], id[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], id[10:])
+	return string(buf)
+}
+
+// IsValid reports whether an inbound request ID is safe to adopt: at most 128
+// characters of letters, digits and . _ : - so it cannot forge log lines or headers
+func IsValid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '.' || r == '_' || r == ':' || r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+type (
+	requestIDKey    struct{}
+	traceContextKey struct{}
+)
+
+// WithContext returns a context carrying the request ID and trace context
+func WithContext(ctx context.Context, id string, trace TraceContext) context.Context {
+	ctx = context.WithValue(ctx, requestIDKey{}, id)
+	return context.WithValue(ctx, traceContextKey{}, trace)
+}
+
+// FromContext returns the request ID of the context, or "" outside a request
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// TraceFromContext returns the trace context of the context
+func TraceFromContext(ctx context.Context) (TraceContext, bool) {
+	trace, ok := ctx.Value(traceContextKey{}).(TraceContext)
+	return trace, ok
+}
+
+// Inject adds the request ID and trace context of ctx to the headers of an
+// outbound request, so the called service can correlate it with this request.
+// The outbound call becomes a child of this server's span.
+func Inject(ctx context.Context, header http.Header) {
+	if id := FromContext(ctx); id != "" {
+		header.Set(Header, id)
+	}
+	if trace, ok := TraceFromContext(ctx); ok {
+		header.Set(TraceparentHeader, trace.String())
+		if trace.State != "" {
+			header.Set(TracestateHeader, trace.State)
+		}
+	}
+}
+
+// mustRead fills b with random bytes. crypto/rand never fails on supported platforms.
+func mustRead(b []byte) {
+	if _, err := rand.Read(b); err != nil {
+		panic("requestid: crypto/rand failed: " + err.Error())
+	}
+}
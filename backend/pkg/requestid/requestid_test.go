@@ -0,0 +1,180 @@
+package requestid_test
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modmastei2/Go-next/backend/pkg/requestid"
+)
+
+// uuidV7Pattern matches a UUID with version 7 and the RFC 9562 variant
+var uuidV7Pattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestNew(t *testing.T) {
+	before := time.Now().UnixMilli()
+	seen := make(map[string]bool)
+	previous := ""
+	for i := 0; i < 1000; i++ {
+		id := requestid.New()
+		if !uuidV7Pattern.MatchString(id) {
+			t.Fatalf("New() = %q, want a UUIDv7", id)
+		}
+		if seen[id] {
+			t.Fatalf("New() returned %q twice", id)
+		}
+		seen[id] = true
+
+		// IDs of different milliseconds sort by time
+		if previous != "" && id[:13] < previous[:13] {
+			t.Errorf("New() = %q sorts before the earlier %q", id, previous)
+		}
+		previous = id
+	}
+
+	// The first 48 bits are the creation time in milliseconds
+	var millis int64
+	for _, c := range strings.ReplaceAll(previous[:13], "-", "") {
+		millis = millis*16 + int64(strings.IndexRune("0123456789abcdef", c))
+	}
+	if after := time.Now().UnixMilli(); millis < before || millis > after {
+		t.Errorf("timestamp of %q is %d, want between %d and %d", previous, millis, before, after)
+	}
+
+	if !requestid.IsValid(previous) {
+		t.Errorf("IsValid(%q) = false for a generated ID", previous)
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{"019b7a44-e800-7a51-9c1d-2f4e6a8b0c3d", true},
+		{"req_42.retry:1", true},
+		{strings.Repeat("a", 128), true},
+		{strings.Repeat("a", 129), false},
+		{"", false},
+		{"id with spaces", false},
+		{"id\nlevel=ERROR msg=forged", false},
+		{"id\r\nSet-Cookie: x=1", false},
+		{`id","level":"ERROR`, false},
+		{"id/../../etc", false},
+		{"idé", false},
+		{"<script>", false},
+	}
+
+	for _, tt := range tests {
+		if got := requestid.IsValid(tt.id); got != tt.want {
+			t.Errorf("IsValid(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestParseTraceparent(t *testing.T) {
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+
+	valid := []struct {
+		value string
+		flags string
+	}{
+		{"00-" + traceID + "-" + spanID + "-01", "01"},
+		{"00-" + traceID + "-" + spanID + "-00", "00"},
+		{"  00-" + traceID + "-" + spanID + "-01 ", "01"},
+		// Later versions may append fields
+		{"01-" + traceID + "-" + spanID + "-01-extra", "01"},
+		{"cc-" + traceID + "-" + spanID + "-01", "01"},
+	}
+	for _, tt := range valid {
+		got, ok := requestid.ParseTraceparent(tt.value)
+		if !ok {
+			t.Errorf("ParseTraceparent(%q) failed", tt.value)
+			continue
+		}
+		if got.TraceID != traceID || got.SpanID != spanID || got.Flags != tt.flags {
+			t.Errorf("ParseTraceparent(%q) = %+v", tt.value, got)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"garbage",
+		"00-" + traceID + "-" + spanID,
+		"00-" + traceID + "-" + spanID + "-01-extra",
+		"ff-" + traceID + "-" + spanID + "-01",
+		"0-" + traceID + "-" + spanID + "-01",
+		"00-" + strings.ToUpper(traceID) + "-" + spanID + "-01",
+		"00-" + traceID[:31] + "-" + spanID + "-01",
+		"00-" + traceID + "-" + spanID[:15] + "-01",
+		"00-" + traceID + "-" + spanID + "-1",
+		"00-" + traceID + "-" + spanID + "-zz",
+		"00-" + strings.Repeat("0", 32) + "-" + spanID + "-01",
+		"00-" + traceID + "-" + strings.Repeat("0", 16) + "-01",
+		"00_" + traceID + "_" + spanID + "_01",
+	}
+	for _, value := range invalid {
+		if got, ok := requestid.ParseTraceparent(value); ok {
+			t.Errorf("ParseTraceparent(%q) = %+v, want it rejected", value, got)
+		}
+	}
+}
+
+func TestNewTrace(t *testing.T) {
+	const inbound = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	// A valid traceparent is continued with a new span of this server
+	trace := requestid.NewTrace(inbound, "vendor=value")
+	if trace.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || trace.ParentID != "00f067aa0ba902b7" || trace.Flags != "01" {
+		t.Errorf("NewTrace continued as %+v", trace)
+	}
+	if trace.SpanID == trace.ParentID || len(trace.SpanID) != 16 {
+		t.Errorf("NewTrace kept span %q, want a new span ID", trace.SpanID)
+	}
+	if trace.State != "vendor=value" {
+		t.Errorf("NewTrace state = %q, want it passed on", trace.State)
+	}
+	if parsed, ok := requestid.ParseTraceparent(trace.String()); !ok || parsed.SpanID != trace.SpanID {
+		t.Errorf("String() = %q does not parse back", trace.String())
+	}
+
+	// Anything else starts a new, unsampled trace and drops the state
+	for _, traceparent := range []string{"", "garbage", "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"} {
+		trace := requestid.NewTrace(traceparent, "vendor=value")
+		if trace.TraceID == "4bf92f3577b34da6a3ce929d0e0e4736" || trace.ParentID != "" || trace.Flags != "00" || trace.State != "" {
+			t.Errorf("NewTrace(%q) = %+v, want a new trace", traceparent, trace)
+		}
+		if _, ok := requestid.ParseTraceparent(trace.String()); !ok {
+			t.Errorf("NewTrace(%q) formats as the invalid %q", traceparent, trace.String())
+		}
+	}
+}
+
+func TestInject(t *testing.T) {
+	header := http.Header{}
+	requestid.Inject(context.Background(), header)
+	if len(header) != 0 {
+		t.Errorf("Inject outside a request set %v", header)
+	}
+
+	trace := requestid.NewTrace("", "")
+	trace.State = "vendor=value"
+	ctx := requestid.WithContext(context.Background(), "req-1", trace)
+	requestid.Inject(ctx, header)
+
+	if got := header.Get(requestid.Header); got != "req-1" {
+		t.Errorf("%s = %q, want req-1", requestid.Header, got)
+	}
+	if got := header.Get(requestid.TraceparentHeader); got != trace.String() {
+		t.Errorf("%s = %q, want %q", requestid.TraceparentHeader, got, trace.String())
+	}
+	if got := header.Get(requestid.TracestateHeader); got != "vendor=value" {
+		t.Errorf("%s = %q, want vendor=value", requestid.TracestateHeader, got)
+	}
+}
@@ -0,0 +1,81 @@
+package requestid
+
+import (
+	"encoding/hex"
+	"strings"
+)
+
+// W3C Trace Context headers
+const (
+	TraceparentHeader = "traceparent"
+	TracestateHeader  = "tracestate"
+)
+
+// TraceContext is a W3C Trace Context (https://www.w3.org/TR/trace-context/).
+// TraceID is shared by every service handling a request, SpanID identifies
+// the operation of this service.
+type TraceContext struct {
+	TraceID  string // 32 lower-case hex characters
+	SpanID   string // 16 lower-case hex characters
+	ParentID string // span of the caller, empty when this service started the trace
+	Flags    string // 2 hex characters, 01 when the caller samples the trace
+	State    string // vendor specific tracestate, passed on unchanged
+}
+
+// NewTrace starts a trace, or continues the caller's trace when traceparent is
+// valid. Either way this service gets a new span ID.
+func NewTrace(traceparent, tracestate string) TraceContext {
+	if trace, ok := ParseTraceparent(traceparent); ok {
+		trace.ParentID = trace.SpanID
+		trace.SpanID = randomHex(8)
+		trace.State = tracestate
+		return trace
+	}
+	return TraceContext{TraceID: randomHex(16), SpanID: randomHex(8), Flags: "00"}
+}
+
+// ParseTraceparent parses a version 00 traceparent header. Later versions are
+// accepted when they start with the same fields, as the specification requires.
+func ParseTraceparent(value string) (TraceContext, bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return TraceContext{}, false
+	}
+
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if !isHex(version, 2) || version == "ff" || (version == "00" && len(parts) != 4) {
+		return TraceContext{}, false
+	}
+	if !isHex(traceID, 32) || !isHex(spanID, 16) || !isHex(flags, 2) {
+		return TraceContext{}, false
+	}
+	if strings.Trim(traceID, "0") == "" || strings.Trim(spanID, "0") == "" {
+		return TraceContext{}, false
+	}
+	return TraceContext{TraceID: traceID, SpanID: spanID, Flags: flags}, true
+}
+
+// String formats the trace context as a traceparent header for this span
+func (t TraceContext) String() string {
+	return "00-" + t.TraceID + "-" + t.SpanID + "-" + t.Flags
+}
+
+// isHex reports whether s is n lower-case hex characters
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// randomHex returns n random bytes as hex
+func randomHex(n int) string {
+	b := make([]byte, n)
+	mustRead(b)
+	return hex.EncodeToString(b)
+}
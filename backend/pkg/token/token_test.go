@@ -0,0 +1,229 @@
+package token_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/modmastei2/Go-next/backend/pkg/token"
+)
+
+const secret = "0123456789abcdef0123456789abcdef"
+
+// writeRSAKeys writes a new PEM encoded key pair and returns the file paths
+// and the encoded public key
+func writeRSAKeys(t *testing.T) (privateFile, publicFile string, publicPEM []byte) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("failed to encode public key: %v", err)
+	}
+
+	dir := t.TempDir()
+	privateFile = filepath.Join(dir, "private.pem")
+	publicFile = filepath.Join(dir, "public.pem")
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	publicPEM = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+	if err := os.WriteFile(privateFile, privatePEM, 0o600); err != nil {
+		t.Fatalf("failed to write private key: %v", err)
+	}
+	if err := os.WriteFile(publicFile, publicPEM, 0o600); err != nil {
+		t.Fatalf("failed to write public key: %v", err)
+	}
+	return privateFile, publicFile, publicPEM
+}
+
+// sign signs claims with the method and key, bypassing Signer so tests can
+// forge tokens it would never issue
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, claims jwt.Claims) string {
+	t.Helper()
+
+	signed, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return signed
+}
+
+// claims returns customer claims expiring after ttl
+func claims(ttl time.Duration) *token.Claims {
+	return &token.Claims{
+		Role:       "customer",
+		CustomerID: 7,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "customer:7",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		},
+	}
+}
+
+func TestNewVerifierRejectsInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  token.Config
+	}{
+		{"short secret", token.Config{Algorithm: token.AlgorithmHS256, Secret: "too-short"}},
+		{"empty secret", token.Config{Algorithm: token.AlgorithmHS256}},
+		{"RS256 without public key", token.Config{Algorithm: token.AlgorithmRS256}},
+		{"RS256 with missing public key", token.Config{Algorithm: token.AlgorithmRS256, PublicKeyFile: "/does/not/exist.pem"}},
+		{"unknown algorithm", token.Config{Algorithm: "none", Secret: secret}},
+	}
+
+	for _, tt := range tests {
+		if _, err := token.NewVerifier(tt.cfg); err == nil {
+			t.Errorf("%s: NewVerifier succeeded, want an error", tt.name)
+		}
+	}
+}
+
+func TestVerifyHS256(t *testing.T) {
+	verifier, err := token.NewVerifier(token.Config{Algorithm: token.AlgorithmHS256, Secret: secret, Issuer: "shop", Audience: "api"})
+	if err != nil {
+		t.Fatalf("NewVerifier failed: %v", err)
+	}
+
+	withRegistered := func(ttl time.Duration, issuer, audience string) *token.Claims {
+		c := claims(ttl)
+		c.Issuer = issuer
+		c.Audience = jwt.ClaimStrings{audience}
+		return c
+	}
+	noExpiry := withRegistered(time.Hour, "shop", "api")
+	noExpiry.ExpiresAt = nil
+
+	tests := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{"valid", sign(t, jwt.SigningMethodHS256, []byte(secret), withRegistered(time.Hour, "shop", "api")), true},
+		{"expired within leeway", sign(t, jwt.SigningMethodHS256, []byte(secret), withRegistered(-10*time.Second, "shop", "api")), true},
+		{"expired", sign(t, jwt.SigningMethodHS256, []byte(secret), withRegistered(-time.Hour, "shop", "api")), false},
+		{"no expiry", sign(t, jwt.SigningMethodHS256, []byte(secret), noExpiry), false},
+		{"bad signature", sign(t, jwt.SigningMethodHS256, []byte(secret+"x"), withRegistered(time.Hour, "shop", "api")), false},
+		{"alg none", sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, withRegistered(time.Hour, "shop", "api")), false},
+		{"HS512", sign(t, jwt.SigningMethodHS512, []byte(secret), withRegistered(time.Hour, "shop", "api")), false},
+		{"wrong issuer", sign(t, jwt.SigningMethodHS256, []byte(secret), withRegistered(time.Hour, "other", "api")), false},
+		{"wrong audience", sign(t, jwt.SigningMethodHS256, []byte(secret), withRegistered(time.Hour, "shop", "other")), false},
+		{"malformed", "not.a.token", false},
+	}
+
+	for _, tt := range tests {
+		got, err := verifier.Verify(tt.token)
+		switch {
+		case tt.valid && err != nil:
+			t.Errorf("%s: Verify failed: %v", tt.name, err)
+		case tt.valid && (got.Subject != "customer:7" || got.Role != "customer" || got.CustomerID != 7):
+			t.Errorf("%s: Verify = %+v, want the signed claims", tt.name, got)
+		case !tt.valid && !errors.Is(err, token.ErrInvalidToken):
+			t.Errorf("%s: Verify = %v, want %v", tt.name, err, token.ErrInvalidToken)
+		}
+	}
+}
+
+func TestVerifyRS256PinsAlgorithm(t *testing.T) {
+	privateFile, publicFile, publicPEM := writeRSAKeys(t)
+	cfg := token.Config{Algorithm: token.AlgorithmRS256, PublicKeyFile: publicFile, PrivateKeyFile: privateFile, AccessTTL: time.Minute}
+
+	verifier, err := token.NewVerifier(cfg)
+	if err != nil {
+		t.Fatalf("NewVerifier failed: %v", err)
+	}
+	signer, err := token.NewSigner(cfg)
+	if err != nil {
+		t.Fatalf("NewSigner failed: %v", err)
+	}
+
+	valid, err := signer.Sign(*claims(0))
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if _, err := verifier.Verify(valid); err != nil {
+		t.Errorf("Verify of a signed token failed: %v", err)
+	}
+
+	// An attacker knowing the public key signs HS256 with it as the secret
+	forged := map[string]string{
+		"HS256 with the public key": sign(t, jwt.SigningMethodHS256, publicPEM, claims(time.Hour)),
+		"alg none":                  sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, claims(time.Hour)),
+	}
+	for name, tokenString := range forged {
+		if _, err := verifier.Verify(tokenString); !errors.Is(err, token.ErrInvalidToken) {
+			t.Errorf("%s: Verify = %v, want %v", name, err, token.ErrInvalidToken)
+		}
+	}
+}
+
+func TestSignerSetsRegisteredClaims(t *testing.T) {
+	cfg := token.Config{Algorithm: token.AlgorithmHS256, Secret: secret, Issuer: "shop", Audience: "api", AccessTTL: 5 * time.Minute}
+	signer, err := token.NewSigner(cfg)
+	if err != nil {
+		t.Fatalf("NewSigner failed: %v", err)
+	}
+	verifier, err := token.NewVerifier(cfg)
+	if err != nil {
+		t.Fatalf("NewVerifier failed: %v", err)
+	}
+
+	signed, err := signer.Sign(token.Claims{Role: "admin", RegisteredClaims: jwt.RegisteredClaims{Subject: "admin:1"}})
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	got, err := verifier.Verify(signed)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+
+	if got.Issuer != "shop" || len(got.Audience) != 1 || got.Audience[0] != "api" {
+		t.Errorf("got issuer %q and audience %v, want shop and [api]", got.Issuer, got.Audience)
+	}
+	if ttl := got.ExpiresAt.Sub(got.IssuedAt.Time); ttl != 5*time.Minute {
+		t.Errorf("token lifetime = %v, want %v", ttl, 5*time.Minute)
+	}
+}
+
+func TestNewSignerRejectsInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  token.Config
+	}{
+		{"short secret", token.Config{Algorithm: token.AlgorithmHS256, Secret: "too-short", AccessTTL: time.Minute}},
+		{"no lifetime", token.Config{Algorithm: token.AlgorithmHS256, Secret: secret}},
+		{"RS256 without private key", token.Config{Algorithm: token.AlgorithmRS256, AccessTTL: time.Minute}},
+	}
+
+	for _, tt := range tests {
+		if _, err := token.NewSigner(tt.cfg); err == nil {
+			t.Errorf("%s: NewSigner succeeded, want an error", tt.name)
+		}
+	}
+}
+
+func TestConfigCanSign(t *testing.T) {
+	tests := []struct {
+		cfg  token.Config
+		want bool
+	}{
+		{token.Config{Algorithm: token.AlgorithmHS256, Secret: secret}, true},
+		{token.Config{Algorithm: token.AlgorithmRS256, PublicKeyFile: "public.pem"}, false},
+		{token.Config{Algorithm: token.AlgorithmRS256, PublicKeyFile: "public.pem", PrivateKeyFile: "private.pem"}, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.cfg.CanSign(); got != tt.want {
+			t.Errorf("%+v.CanSign() = %v, want %v", tt.cfg, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package tracing
+
+import (
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// spanKey is the statement setting holding the span of a query
+const spanKey = "tracing:span"
+
+// GormPlugin starts a client span for every GORM operation. Preloads run as
+// separate queries and get spans of their own.
+type GormPlugin struct {
+	tracer trace.Tracer
+}
+
+// NewGormPlugin creates the query tracing plugin, install it with db.Use
+func NewGormPlugin() *GormPlugin {
+	return &GormPlugin{tracer: otel.Tracer("github.com/modmastei2/Go-next/backend/pkg/tracing")}
+}
+
+// Name implements gorm.Plugin
+func (p *GormPlugin) Name() string {
+	return "tracing"
+}
+
+// Initialize implements gorm.Plugin by wrapping each callback chain in a span
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	chains := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", callbacks.Create().Before("gorm:create").Register, callbacks.Create().After("gorm:create").Register},
+		{"query", callbacks.Query().Before("gorm:query").Register, callbacks.Query().After("gorm:query").Register},
+		{"update", callbacks.Update().Before("gorm:update").Register, callbacks.Update().After("gorm:update").Register},
+		{"delete", callbacks.Delete().Before("gorm:delete").Register, callbacks.Delete().After("gorm:delete").Register},
+		{"row", callbacks.Row().Before("gorm:row").Register, callbacks.Row().After("gorm:row").Register},
+		{"raw", callbacks.Raw().Before("gorm:raw").Register, callbacks.Raw().After("gorm:raw").Register},
+	}
+	for _, chain := range chains {
+		if err := chain.before("tracing:before_"+chain.operation, p.start(db.Dialector.Name())); err != nil {
+			return err
+		}
+		if err := chain.after("tracing:after_"+chain.operation, end); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// start returns a callback starting the span of a statement. The span is
+// named once the SQL is known.
+func (p *GormPlugin) start(system string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		_, span := p.tracer.Start(db.Statement.Context, "db",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemNameKey.String(system)))
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+// end names the span after the executed statement and records its outcome.
+// The query text holds placeholders, never the bound values.
+func end(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	query := db.Statement.SQL.String()
+	operation, _, _ := strings.Cut(strings.TrimSpace(query), " ")
+	operation = strings.ToUpper(operation)
+	table := db.Statement.Table
+
+	span.SetName(strings.TrimSpace(operation + " " + table))
+	span.SetAttributes(
+		semconv.DBOperationName(operation),
+		semconv.DBCollectionName(table),
+		semconv.DBQueryText(query),
+		attribute.Int64("db.rows_affected", db.RowsAffected),
+	)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
@@ -0,0 +1,94 @@
+'use client';
+
+import { useState } from 'react';
+import { api } from '@/lib/api';
+import type { ApiError } from '@/lib/api/http-client';
+
+export function LoginForm() {
+  const [mode, setMode] = useState<'login' | 'register'>('login');
+  const [name, setName] = useState('');
+  const [email, setEmail] = useState('');
+  const [password, setPassword] = useState('');
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+
+  // Signing in updates the session, pages follow it through onSessionChange
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setLoading(true);
+    setError(null);
+
+    try {
+      if (mode === 'register') {
+        await api.auth.register({ name, email, password });
+      }
+      await api.auth.login(email, password);
+    } catch (err) {
+      setError((err as ApiError).message || 'Failed to sign in. Please try again.');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const inputClass =
+    'w-full px-4 py-2 border border-gray-300 rounded-lg focus:ring-2 focus:ring-blue-500 focus:border-blue-500';
+
+  return (
+    <form onSubmit={handleSubmit} className="space-y-4">
+      {error && <div className="p-3 rounded-lg bg-red-50 text-red-700 text-sm">{error}</div>}
+
+      {mode === 'register' && (
+        <div>
+          <label htmlFor="name" className="block text-sm font-medium text-gray-700 mb-2">
+            Name
+          </label>
+          <input id="name" value={name} onChange={(e) => setName(e.target.value)} className={inputClass} required />
+        </div>
+      )}
+      <div>
+        <label htmlFor="email" className="block text-sm font-medium text-gray-700 mb-2">
+          Email
+        </label>
+        <input
+          type="email"
+          id="email"
+          autoComplete="email"
+          value={email}
+          onChange={(e) => setEmail(e.target.value)}
+          className={inputClass}
+          required
+        />
+      </div>
+      <div>
+        <label htmlFor="password" className="block text-sm font-medium text-gray-700 mb-2">
+          Password
+        </label>
+        <input
+          type="password"
+          id="password"
+          autoComplete={mode === 'register' ? 'new-password' : 'current-password'}
+          minLength={mode === 'register' ? 8 : undefined}
+          value={password}
+          onChange={(e) => setPassword(e.target.value)}
+          className={inputClass}
+          required
+        />
+      </div>
+
+      <button
+        type="submit"
+        disabled={loading}
+        className="w-full bg-blue-600 text-white py-3 rounded-lg hover:bg-blue-700 disabled:bg-gray-300 disabled:cursor-not-allowed transition-colors font-semibold"
+      >
+        {loading ? 'Signing in...' : mode === 'login' ? 'Sign In' : 'Create Account'}
+      </button>
+      <button
+        type="button"
+        onClick={() => setMode(mode === 'login' ? 'register' : 'login')}
+        className="w-full text-sm text-blue-600 hover:underline"
+      >
+        {mode === 'login' ? 'New here? Create an account' : 'Already have an account? Sign in'}
+      </button>
+    </form>
+  );
+}
@@ -2,9 +2,16 @@
 
 import { useState, useEffect } from 'react';
 import { api } from '@/lib/api';
-import type { Order } from '@/lib/api/types';
+import type { ApiError } from '@/lib/api/http-client';
+import type { Order, OrderStatus } from '@/lib/api/types';
+import { formatMoney, multiplyMoney } from '@/lib/money';
 
-export function OrderList() {
+interface OrderListProps {
+  // Only staff and admins may change the status of an order
+  canUpdateStatus?: boolean;
+}
+
+export function OrderList({ canUpdateStatus = false }: OrderListProps) {
   const [orders, setOrders] = useState<Order[]>([]);
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
@@ -26,11 +33,19 @@ export function OrderList() {
     loadOrders();
   }, []);
 
-  const handleStatusUpdate = async (orderId: number, newStatus: string) => {
+  const handleStatusUpdate = async (orderId: number, newStatus: OrderStatus) => {
+    // The API requires a reason when cancelling an order
+    let reason: string | undefined;
+    if (newStatus === 'cancelled') {
+      reason = window.prompt('Why is this order being cancelled?') || undefined;
+      if (!reason) return;
+    }
+
     try {
-      await api.orders.updateStatus(orderId, newStatus);
+      await api.orders.updateStatus(orderId, newStatus, reason);
       await loadOrders();
     } catch (err) {
+      window.alert(`Failed to update order #${orderId}: ${(err as ApiError).message}`);
       console.error('Failed to update order status:', err);
     }
   };
@@ -93,7 +108,7 @@ export function OrderList() {
               <span className={`px-3 py-1 rounded-full text-sm font-semibold ${getStatusColor(order.status)}`}>
                 {order.status}
               </span>
-              <p className="text-xl font-bold text-blue-600">${order.total.toFixed(2)}</p>
+              <p className="text-xl font-bold text-blue-600">{formatMoney(order.total)}</p>
             </div>
           </div>
 
@@ -106,25 +121,27 @@ export function OrderList() {
                     {item.product?.name || `Product ID ${item.product_id}`} x {item.quantity}
                   </span>
                   <span className="font-semibold text-gray-900">
-                    ${((item.price || 0) * item.quantity).toFixed(2)}
+                    {item.price ? formatMoney(multiplyMoney(item.price, item.quantity)) : '-'}
                   </span>
                 </div>
               ))}
             </div>
           </div>
 
-          <div className="flex gap-2 mt-4 pt-4 border-t">
-            <select
-              value={order.status}
-              onChange={(e) => handleStatusUpdate(order.id, e.target.value)}
-              className="px-3 py-2 border border-gray-300 rounded-lg text-sm focus:ring-2 focus:ring-blue-500 focus:border-blue-500"
-            >
-              <option value="pending">Pending</option>
-              <option value="processing">Processing</option>
-              <option value="completed">Completed</option>
-              <option value="cancelled">Cancelled</option>
-            </select>
-          </div>
+          {canUpdateStatus && (
+            <div className="flex gap-2 mt-4 pt-4 border-t">
+              <select
+                value={order.status}
+                onChange={(e) => handleStatusUpdate(order.id, e.target.value as OrderStatus)}
+                className="px-3 py-2 border border-gray-300 rounded-lg text-sm focus:ring-2 focus:ring-blue-500 focus:border-blue-500"
+              >
+                <option value="pending">Pending</option>
+                <option value="processing">Processing</option>
+                <option value="completed">Completed</option>
+                <option value="cancelled">Cancelled</option>
+              </select>
+            </div>
+          )}
         </div>
       ))}
     </div>
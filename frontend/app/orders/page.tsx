@@ -2,8 +2,12 @@
 
 import { useState, useEffect } from 'react';
 import { api } from '@/lib/api';
+import type { ApiError } from '@/lib/api/http-client';
+import { onSessionChange, type Session } from '@/lib/api/session';
 import type { Product } from '@/lib/api/types';
+import { LoginForm } from '../components/LoginForm';
 import { OrderList } from '../components/OrderList';
+import { formatMoney, multiplyMoney, sumMoney } from '@/lib/money';
 
 interface CartItem extends Product {
   quantity: number;
@@ -16,11 +20,33 @@ export default function OrdersPage() {
   const [loading, setLoading] = useState(false);
   const [message, setMessage] = useState<{ type: 'success' | 'error'; text: string } | null>(null);
   const [refreshOrders, setRefreshOrders] = useState(0);
+  const [session, setSession] = useState<Session | null>(null);
+  const [restoring, setRestoring] = useState(true);
 
   useEffect(() => {
     loadProducts();
+
+    // Orders need a signed-in user, the product catalog is public
+    const unsubscribe = onSessionChange(setSession);
+    api.auth
+      .restore()
+      .then(setSession)
+      .finally(() => setRestoring(false));
+    return unsubscribe;
   }, []);
 
+  const handleLogout = async () => {
+    if (!session) return;
+    try {
+      await api.auth.logout(session.refreshToken);
+    } catch (err) {
+      console.error('Failed to revoke session:', err);
+    }
+  };
+
+  // Customers may only order for themselves, staff order on behalf of a customer
+  const isCustomer = session?.role === 'customer';
+
   const loadProducts = async () => {
     try {
       const data = await api.products.getAll(20, 0);
@@ -55,7 +81,7 @@ export default function OrdersPage() {
   };
 
   const calculateTotal = () => {
-    return cart.reduce((total, item) => total + item.price * item.quantity, 0);
+    return sumMoney(cart.map((item) => multiplyMoney(item.price, item.quantity)));
   };
 
   const handleSubmitOrder = async (e: React.FormEvent) => {
@@ -71,7 +97,7 @@ export default function OrdersPage() {
 
     try {
       await api.orders.create({
-        customer_id: parseInt(customerId),
+        customer_id: isCustomer && session?.customerId ? session.customerId : parseInt(customerId),
         items: cart.map((item) => ({
           product_id: item.id,
           quantity: item.quantity,
@@ -81,7 +107,7 @@ export default function OrdersPage() {
       setCart([]);
       setRefreshOrders((prev) => prev + 1);
     } catch (err) {
-      setMessage({ type: 'error', text: 'Failed to create order. Please try again.' });
+      setMessage({ type: 'error', text: (err as ApiError).message || 'Failed to create order. Please try again.' });
       console.error(err);
     } finally {
       setLoading(false);
@@ -91,11 +117,24 @@ export default function OrdersPage() {
   return (
     <div className="min-h-screen bg-gray-50">
       <div className="max-w-7xl mx-auto px-4 py-8">
-        <header className="mb-8">
-          <h1 className="text-4xl font-bold text-gray-900 mb-2">Shop Orders</h1>
-          <p className="text-gray-600">
-            Browse products, add to cart, and place orders - A demonstration of Next.js + Go backend integration
-          </p>
+        <header className="mb-8 flex justify-between items-start gap-4">
+          <div>
+            <h1 className="text-4xl font-bold text-gray-900 mb-2">Shop Orders</h1>
+            <p className="text-gray-600">
+              Browse products, add to cart, and place orders - A demonstration of Next.js + Go backend integration
+            </p>
+          </div>
+          {session && (
+            <div className="flex items-center gap-4 text-sm text-gray-600">
+              <span>
+                Signed in as {session.role}
+                {isCustomer && ` #${session.customerId}`}
+              </span>
+              <button onClick={handleLogout} className="px-4 py-2 bg-gray-200 rounded-lg hover:bg-gray-300">
+                Sign Out
+              </button>
+            </div>
+          )}
         </header>
 
         {message && (
@@ -123,7 +162,7 @@ export default function OrdersPage() {
                   </div>
                   <div className="flex items-center justify-between mt-4">
                     <div>
-                      <p className="text-2xl font-bold text-blue-600">${product.price.toFixed(2)}</p>
+                      <p className="text-2xl font-bold text-blue-600">{formatMoney(product.price)}</p>
                       <p className="text-sm text-gray-500">Stock: {product.stock}</p>
                     </div>
                     <button
@@ -152,7 +191,7 @@ export default function OrdersPage() {
                     <div key={item.id} className="flex items-center justify-between border-b pb-4">
                       <div className="flex-1">
                         <h3 className="font-semibold text-gray-900">{item.name}</h3>
-                        <p className="text-sm text-gray-600">${item.price.toFixed(2)} each</p>
+                        <p className="text-sm text-gray-600">{formatMoney(item.price)} each</p>
                       </div>
                       <div className="flex items-center gap-4">
                         <div className="flex items-center gap-2">
@@ -171,7 +210,7 @@ export default function OrdersPage() {
                           </button>
                         </div>
                         <p className="font-bold text-gray-900 w-20 text-right">
-                          ${(item.price * item.quantity).toFixed(2)}
+                          {formatMoney(multiplyMoney(item.price, item.quantity))}
                         </p>
                       </div>
                     </div>
@@ -180,43 +219,63 @@ export default function OrdersPage() {
                   <div className="pt-4 border-t">
                     <div className="flex justify-between items-center mb-4">
                       <span className="text-xl font-bold text-gray-900">Total:</span>
-                      <span className="text-2xl font-bold text-blue-600">${calculateTotal().toFixed(2)}</span>
+                      <span className="text-2xl font-bold text-blue-600">{formatMoney(calculateTotal() ?? { amount: '0', currency: 'USD' })}</span>
                     </div>
 
-                    <form onSubmit={handleSubmitOrder}>
-                      <div className="mb-4">
-                        <label htmlFor="customerId" className="block text-sm font-medium text-gray-700 mb-2">
-                          Customer ID
-                        </label>
-                        <input
-                          type="number"
-                          id="customerId"
-                          value={customerId}
-                          onChange={(e) => setCustomerId(e.target.value)}
-                          className="w-full px-4 py-2 border border-gray-300 rounded-lg focus:ring-2 focus:ring-blue-500 focus:border-blue-500"
-                          required
-                        />
-                      </div>
+                    {!session ? (
+                      <p className="text-gray-600 text-center">Sign in below to place your order</p>
+                    ) : (
+                      <form onSubmit={handleSubmitOrder}>
+                        {!isCustomer && (
+                          <div className="mb-4">
+                            <label htmlFor="customerId" className="block text-sm font-medium text-gray-700 mb-2">
+                              Customer ID
+                            </label>
+                            <input
+                              type="number"
+                              id="customerId"
+                              value={customerId}
+                              onChange={(e) => setCustomerId(e.target.value)}
+                              className="w-full px-4 py-2 border border-gray-300 rounded-lg focus:ring-2 focus:ring-blue-500 focus:border-blue-500"
+                              required
+                            />
+                          </div>
+                        )}
 
-                      <button
-                        type="submit"
-                        disabled={loading}
-                        className="w-full bg-blue-600 text-white py-3 rounded-lg hover:bg-blue-700 disabled:bg-gray-300 disabled:cursor-not-allowed transition-colors font-semibold"
-                      >
-                        {loading ? 'Creating Order...' : 'Place Order'}
-                      </button>
-                    </form>
+                        <button
+                          type="submit"
+                          disabled={loading}
+                          className="w-full bg-blue-600 text-white py-3 rounded-lg hover:bg-blue-700 disabled:bg-gray-300 disabled:cursor-not-allowed transition-colors font-semibold"
+                        >
+                          {loading ? 'Creating Order...' : 'Place Order'}
+                        </button>
+                      </form>
+                    )}
                   </div>
                 </div>
               )}
             </div>
+
+            {/* Sign In Section */}
+            {!session && !restoring && (
+              <div className="bg-white rounded-lg shadow-md p-6 mt-8">
+                <h2 className="text-2xl font-bold mb-6 text-gray-900">Sign In</h2>
+                <LoginForm />
+              </div>
+            )}
           </div>
         </div>
 
         {/* Orders Section */}
         <div className="mt-12">
-          <h2 className="text-2xl font-bold mb-6 text-gray-900">Recent Orders</h2>
-          <OrderList key={refreshOrders} />
+          <h2 className="text-2xl font-bold mb-6 text-gray-900">{isCustomer ? 'My Orders' : 'Recent Orders'}</h2>
+          {session ? (
+            <OrderList key={`${session.subject}-${refreshOrders}`} canUpdateStatus={!isCustomer} />
+          ) : (
+            <div className="bg-gray-50 border border-gray-200 rounded-lg p-8 text-center">
+              <p className="text-gray-600">Sign in to see your orders.</p>
+            </div>
+          )}
         </div>
       </div>
     </div>
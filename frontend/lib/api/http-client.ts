@@ -8,6 +8,9 @@ const API_BASE_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:3001/a
 export interface ApiError {
   message: string;
   status?: number;
+  code?: string;
+  requestId?: string;
+  errors?: { field: string; rule: string; message: string }[];
 }
 
 export interface RequestInterceptor {
@@ -101,9 +104,14 @@ class HttpClient {
 
       // Handle errors
       if (!response.ok) {
+        // Errors are RFC 7807 problem+json documents
+        const problem = await response.json().catch(() => null);
         const error: ApiError = {
-          message: `HTTP error! status: ${response.status}`,
+          message: problem?.detail ?? `HTTP error! status: ${response.status}`,
           status: response.status,
+          code: problem?.code,
+          requestId: problem?.request_id,
+          errors: problem?.errors,
         };
         throw error;
       }
@@ -142,6 +150,13 @@ class HttpClient {
     });
   }
 
+  patch<T>(endpoint: string, data?: unknown): Promise<T> {
+    return this.request<T>(endpoint, {
+      method: 'PATCH',
+      body: JSON.stringify(data),
+    });
+  }
+
   delete<T>(endpoint: string): Promise<T> {
     return this.request<T>(endpoint, { method: 'DELETE' });
   }
@@ -172,18 +187,24 @@ httpClient.addResponseInterceptor({
   },
 });
 
-// Example: Add authentication interceptor (can be uncommented when auth is implemented)
-// httpClient.addRequestInterceptor({
-//   onRequest: (config) => {
-//     const token = localStorage.getItem('token');
-//     if (token) {
-//       config.headers = {
-//         ...config.headers,
-//         Authorization: `Bearer ${token}`,
-//       };
-//     }
-//     return config;
-//   },
-// });
+// Access token sent as a bearer token, set after signing in
+let accessToken: string | null = null;
+
+export const setAccessToken = (token: string | null) => {
+  accessToken = token;
+};
+
+// Add authentication interceptor
+httpClient.addRequestInterceptor({
+  onRequest: (config) => {
+    if (accessToken) {
+      config.headers = {
+        ...(config.headers as Record<string, string>),
+        Authorization: `Bearer ${accessToken}`,
+      };
+    }
+    return config;
+  },
+});
 
 export default httpClient;
@@ -4,15 +4,49 @@
  */
 
 import httpClient from './http-client';
-import type { Product, Order, CreateOrderRequest, ApiResponse } from './types';
+import { endSession, loadStoredSession, resumeSession, startSession, type Session } from './session';
+import type {
+  Customer,
+  Product,
+  ProductInput,
+  ProductFilters,
+  OrderFilters,
+  Order,
+  OrderStatus,
+  OrderStatusHistory,
+  CreateOrderRequest,
+  ApiResponse,
+  PaginatedResponse,
+  RegisterRequest,
+  TokenResponse,
+} from './types';
+
+let pendingRestore: Promise<Session | null> | null = null;
+
+const restoreSession = async (): Promise<Session | null> => {
+  const stored = loadStoredSession();
+  if (!stored) return null;
+  if (stored.expiresAt > Date.now() + 30_000) {
+    resumeSession(stored);
+    return stored;
+  }
+  try {
+    return await api.auth.refresh(stored.refreshToken);
+  } catch {
+    endSession();
+    return null;
+  }
+};
 
 export const api = {
   // Product endpoints
   products: {
-    getAll: async (limit = 10, offset = 0): Promise<Product[]> => {
-      const response = await httpClient.get<ApiResponse<Product[]>>(
-        `/products?limit=${limit}&offset=${offset}`
-      );
+    getAll: async (limit = 10, offset = 0, filters: ProductFilters = {}): Promise<Product[]> => {
+      const params = new URLSearchParams({ limit: String(limit), offset: String(offset) });
+      Object.entries(filters).forEach(([key, value]) => {
+        if (value !== undefined && value !== '') params.set(key, String(value));
+      });
+      const response = await httpClient.get<PaginatedResponse<Product>>(`/products?${params}`);
       return response.data;
     },
 
@@ -21,27 +55,79 @@ export const api = {
       return response.data;
     },
 
-    create: async (product: Omit<Product, 'id' | 'created_at' | 'updated_at'>): Promise<Product> => {
+    create: async (product: ProductInput): Promise<Product> => {
       const response = await httpClient.post<ApiResponse<Product>>('/products', product);
       return response.data;
     },
 
-    update: async (id: number, product: Partial<Product>): Promise<Product> => {
+    // Replaces every editable field
+    update: async (id: number, product: ProductInput): Promise<Product> => {
       const response = await httpClient.put<ApiResponse<Product>>(`/products/${id}`, product);
       return response.data;
     },
 
+    // Changes only the given fields
+    patch: async (id: number, changes: Partial<ProductInput>): Promise<Product> => {
+      const response = await httpClient.patch<ApiResponse<Product>>(`/products/${id}`, changes);
+      return response.data;
+    },
+
     delete: async (id: number): Promise<void> => {
       await httpClient.delete(`/products/${id}`);
     },
   },
 
+  // Customer endpoints
+  customers: {
+    getAll: async (limit = 10, offset = 0): Promise<Customer[]> => {
+      const response = await httpClient.get<PaginatedResponse<Customer>>(
+        `/customers?limit=${limit}&offset=${offset}`
+      );
+      return response.data;
+    },
+
+    getById: async (id: number): Promise<Customer> => {
+      const response = await httpClient.get<ApiResponse<Customer>>(`/customers/${id}`);
+      return response.data;
+    },
+
+    getByEmail: async (email: string): Promise<Customer> => {
+      const response = await httpClient.get<ApiResponse<Customer>>(
+        `/customers/lookup?email=${encodeURIComponent(email)}`
+      );
+      return response.data;
+    },
+
+    getOrders: async (id: number, limit = 10, offset = 0): Promise<Order[]> => {
+      const response = await httpClient.get<PaginatedResponse<Order>>(
+        `/customers/${id}/orders?limit=${limit}&offset=${offset}`
+      );
+      return response.data;
+    },
+
+    create: async (customer: Pick<Customer, 'name' | 'email'>): Promise<Customer> => {
+      const response = await httpClient.post<ApiResponse<Customer>>('/customers', customer);
+      return response.data;
+    },
+
+    update: async (id: number, customer: Pick<Customer, 'name' | 'email'>): Promise<Customer> => {
+      const response = await httpClient.put<ApiResponse<Customer>>(`/customers/${id}`, customer);
+      return response.data;
+    },
+
+    delete: async (id: number): Promise<void> => {
+      await httpClient.delete(`/customers/${id}`);
+    },
+  },
+
   // Order endpoints
   orders: {
-    getAll: async (limit = 10, offset = 0): Promise<Order[]> => {
-      const response = await httpClient.get<ApiResponse<Order[]>>(
-        `/orders?limit=${limit}&offset=${offset}`
-      );
+    getAll: async (limit = 10, offset = 0, filters: OrderFilters = {}): Promise<Order[]> => {
+      const params = new URLSearchParams({ limit: String(limit), offset: String(offset) });
+      Object.entries(filters).forEach(([key, value]) => {
+        if (value !== undefined && value !== '') params.set(key, String(value));
+      });
+      const response = await httpClient.get<PaginatedResponse<Order>>(`/orders?${params}`);
       return response.data;
     },
 
@@ -55,14 +141,69 @@ export const api = {
       return response.data;
     },
 
-    updateStatus: async (id: number, status: string): Promise<void> => {
-      await httpClient.put(`/orders/${id}/status`, { status });
+    updateStatus: async (id: number, status: OrderStatus, reason?: string): Promise<void> => {
+      await httpClient.put(`/orders/${id}/status`, { status, reason });
+    },
+
+    getHistory: async (id: number): Promise<OrderStatusHistory[]> => {
+      const response = await httpClient.get<ApiResponse<OrderStatusHistory[]>>(
+        `/orders/${id}/history`
+      );
+      return response.data;
     },
 
     delete: async (id: number): Promise<void> => {
       await httpClient.delete(`/orders/${id}`);
     },
   },
+
+  // Account endpoints, successful sign-ins send the access token on later requests
+  auth: {
+    register: async (account: RegisterRequest): Promise<Customer> => {
+      const response = await httpClient.post<ApiResponse<Customer>>('/auth/register', account);
+      return response.data;
+    },
+
+    login: async (email: string, password: string): Promise<Session> => {
+      const response = await httpClient.post<ApiResponse<TokenResponse>>('/auth/login', { email, password });
+      return startSession(response.data);
+    },
+
+    // Refresh tokens are single use, the new session keeps the returned one
+    refresh: async (refreshToken: string): Promise<Session> => {
+      const response = await httpClient.post<ApiResponse<TokenResponse>>('/auth/refresh', {
+        refresh_token: refreshToken,
+      });
+      return startSession(response.data);
+    },
+
+    // Signs out locally even when the API cannot be reached
+    logout: async (refreshToken: string): Promise<void> => {
+      try {
+        await httpClient.post('/auth/logout', { refresh_token: refreshToken });
+      } finally {
+        endSession();
+      }
+    },
+
+    // Resumes the session of an earlier page load, refreshing an expired access token.
+    // Concurrent callers share one refresh, presenting a used refresh token again
+    // revokes every session of the customer.
+    restore: (): Promise<Session | null> => {
+      pendingRestore ??= restoreSession().finally(() => {
+        pendingRestore = null;
+      });
+      return pendingRestore;
+    },
+
+    requestPasswordReset: async (email: string): Promise<void> => {
+      await httpClient.post('/auth/password-reset', { email });
+    },
+
+    resetPassword: async (token: string, password: string): Promise<void> => {
+      await httpClient.post('/auth/password-reset/confirm', { token, password });
+    },
+  },
 };
 
 export default api;
@@ -0,0 +1,89 @@
+/**
+ * Signed-in session of the shop
+ * Keeps the tokens in localStorage so a reload stays signed in, and sends the
+ * access token on every request
+ */
+
+import httpClient, { setAccessToken } from './http-client';
+import type { Role, TokenResponse } from './types';
+
+export interface Session {
+  accessToken: string;
+  refreshToken: string;
+  expiresAt: number; // access token expiry, milliseconds since the epoch
+  subject: string;
+  role: Role;
+  customerId?: number; // set for customers, who may only order for themselves
+}
+
+const STORAGE_KEY = 'shop.session';
+
+let session: Session | null = null;
+const listeners = new Set<(session: Session | null) => void>();
+
+// Claims of the access token, the signature is checked by the API
+const decodeClaims = (token: string): { sub: string; role: Role; customer_id?: number } => {
+  const payload = token.split('.')[1].replace(/-/g, '+').replace(/_/g, '/');
+  return JSON.parse(atob(payload));
+};
+
+const setSession = (next: Session | null) => {
+  session = next;
+  setAccessToken(next?.accessToken ?? null);
+  if (typeof window !== 'undefined') {
+    if (next) {
+      window.localStorage.setItem(STORAGE_KEY, JSON.stringify(next));
+    } else {
+      window.localStorage.removeItem(STORAGE_KEY);
+    }
+  }
+  listeners.forEach((listener) => listener(next));
+};
+
+// Starts a session from the tokens returned by login or refresh
+export const startSession = (tokens: TokenResponse): Session => {
+  const claims = decodeClaims(tokens.access_token);
+  const next: Session = {
+    accessToken: tokens.access_token,
+    refreshToken: tokens.refresh_token,
+    expiresAt: Date.now() + tokens.expires_in * 1000,
+    subject: claims.sub,
+    role: claims.role,
+    customerId: claims.customer_id,
+  };
+  setSession(next);
+  return next;
+};
+
+export const endSession = () => setSession(null);
+
+// Reads the session stored by an earlier page load, it may need a refresh
+export const loadStoredSession = (): Session | null => {
+  if (typeof window === 'undefined') return null;
+  try {
+    const stored = window.localStorage.getItem(STORAGE_KEY);
+    return stored ? (JSON.parse(stored) as Session) : null;
+  } catch {
+    return null;
+  }
+};
+
+// Makes a stored session current without refreshing it
+export const resumeSession = (stored: Session) => setSession(stored);
+
+// Notifies the listener whenever the user signs in or out
+export const onSessionChange = (listener: (session: Session | null) => void) => {
+  listeners.add(listener);
+  return () => {
+    listeners.delete(listener);
+  };
+};
+
+// An access token the API rejects signs the user out
+httpClient.addResponseInterceptor({
+  onError: (error) => {
+    if (error.status === 401 && session) {
+      endSession();
+    }
+  },
+});
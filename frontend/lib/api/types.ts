@@ -2,16 +2,40 @@
  * Type definitions for API entities
  */
 
+// Exact amount with an ISO 4217 currency code, amount is a decimal string such as "29.99"
+export interface Money {
+  amount: string;
+  currency: string;
+}
+
 export interface Product {
   id: number;
   name: string;
   description: string;
-  price: number;
+  price: Money;
   stock: number;
   created_at: string;
   updated_at: string;
 }
 
+export interface ProductInput {
+  name: string;
+  description: string;
+  price: Money;
+  stock: number;
+}
+
+export interface ProductFilters {
+  q?: string;
+  min_price?: string;
+  max_price?: string;
+  currency?: string;
+  in_stock?: boolean;
+  created_after?: string;
+  sort?: 'price' | 'name' | 'created_at';
+  order?: 'asc' | 'desc';
+}
+
 export interface Customer {
   id: number;
   name: string;
@@ -26,7 +50,33 @@ export interface OrderItem {
   product_id: number;
   product?: Product;
   quantity: number;
-  price?: number;
+  price?: Money;
+}
+
+export type OrderStatus = 'pending' | 'processing' | 'completed' | 'cancelled';
+
+export interface OrderStatusHistory {
+  id: number;
+  order_id: number;
+  from_status: OrderStatus | '';
+  to_status: OrderStatus;
+  changed_by: string;
+  reason: string;
+  created_at: string;
+}
+
+export interface OrderFilters {
+  status?: OrderStatus;
+  customer_id?: number;
+  customer_email?: string;
+  product_id?: number;
+  created_after?: string;
+  created_before?: string;
+  min_total?: string;
+  max_total?: string;
+  currency?: string;
+  sort?: 'created_at' | 'total' | 'status';
+  order?: 'asc' | 'desc';
 }
 
 export interface Order {
@@ -34,8 +84,8 @@ export interface Order {
   customer_id: number;
   customer?: Customer;
   items: OrderItem[];
-  total: number;
-  status: 'pending' | 'processing' | 'completed' | 'cancelled';
+  total: Money;
+  status: OrderStatus;
   created_at: string;
   updated_at: string;
 }
@@ -52,3 +102,30 @@ export interface ApiResponse<T> {
   data: T;
   message?: string;
 }
+
+export interface Pagination {
+  total: number;
+  limit: number;
+  next_cursor: string | null;
+  prev_cursor: string | null;
+}
+
+export interface PaginatedResponse<T> {
+  data: T[];
+  pagination: Pagination;
+}
+
+export type Role = 'admin' | 'staff' | 'customer';
+
+export interface RegisterRequest {
+  name: string;
+  email: string;
+  password: string;
+}
+
+export interface TokenResponse {
+  access_token: string;
+  token_type: 'Bearer';
+  expires_in: number;
+  refresh_token: string;
+}
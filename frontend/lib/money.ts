@@ -0,0 +1,38 @@
+/**
+ * Helpers for exact money values returned by the API
+ * Amounts are decimal strings with the currency's minor digits ("29.99"),
+ * so arithmetic is done in integer minor units to avoid float drift
+ */
+
+import type { Money } from './api/types';
+
+// Number of minor unit digits in an API amount, e.g. 2 for "29.99"
+const minorDigits = (money: Money): number => money.amount.split('.')[1]?.length ?? 0;
+
+// Convert "29.99" to integer minor units (2999)
+const toMinorUnits = (money: Money): number => parseInt(money.amount.replace('.', ''), 10);
+
+// Convert integer minor units back to a decimal amount
+const fromMinorUnits = (minor: number, digits: number, currency: string): Money => {
+  const sign = minor < 0 ? '-' : '';
+  const padded = Math.abs(minor).toString().padStart(digits + 1, '0');
+  const amount = digits === 0 ? padded : `${padded.slice(0, -digits)}.${padded.slice(-digits)}`;
+  return { amount: sign + amount, currency };
+};
+
+// Multiply a price by a quantity
+export const multiplyMoney = (money: Money, quantity: number): Money =>
+  fromMinorUnits(toMinorUnits(money) * quantity, minorDigits(money), money.currency);
+
+// Sum amounts of the same currency, returns null for an empty list
+export const sumMoney = (values: Money[]): Money | null => {
+  if (values.length === 0) return null;
+  const minor = values.reduce((total, value) => total + toMinorUnits(value), 0);
+  return fromMinorUnits(minor, minorDigits(values[0]), values[0].currency);
+};
+
+// Format money for display, e.g. "$29.99"
+export const formatMoney = (money: Money): string =>
+  new Intl.NumberFormat(undefined, { style: 'currency', currency: money.currency }).format(
+    Number(money.amount)
+  );